@@ -3,17 +3,24 @@ package app
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/imran-vz/gosqlit/internal/config"
 	"github.com/imran-vz/gosqlit/internal/db"
 	"github.com/imran-vz/gosqlit/internal/debug"
+	"github.com/imran-vz/gosqlit/internal/export"
 	"github.com/imran-vz/gosqlit/internal/ui/connected"
 	"github.com/imran-vz/gosqlit/internal/ui/explorer"
 	"github.com/imran-vz/gosqlit/internal/ui/modal"
+	"github.com/imran-vz/gosqlit/pkg/treeview"
 )
 
+// queryPageSize is the number of rows fetched per page of results
+const queryPageSize = 100
+
 // New creates new app
 func New(configMgr *config.Manager) *App {
 	connections := configMgr.GetConnections()
@@ -67,22 +74,27 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 							// Save to config file
 							if cfgMgr, ok := a.configMgr.(*config.Manager); ok {
-								if err := cfgMgr.AddConnection(conn); err != nil {
+								save := cfgMgr.AddConnection
+								if connForm.IsEdit() {
+									save = cfgMgr.UpdateConnection
+								}
+								if err := save(conn); err != nil {
 									fmt.Printf("Failed to save connection: %v\n", err)
 								}
 							}
 
-							// Add to in-memory list
-							a.connections.saved = append(a.connections.saved, SavedConnection{
-								ID:       conn.ID,
-								Name:     conn.Name,
-								Driver:   conn.Driver,
-								Host:     conn.Host,
-								Port:     conn.Port,
-								Username: conn.Username,
-								Password: conn.Password,
-								Database: conn.Database,
-							})
+							// Add to or replace in in-memory list
+							saved := ToSavedConnections([]config.SavedConnection{conn})[0]
+							replaced := false
+							for i, c := range a.connections.saved {
+								if c.ID == saved.ID {
+									a.connections.saved[i] = saved
+									replaced = true
+								}
+							}
+							if !replaced {
+								a.connections.saved = append(a.connections.saved, saved)
+							}
 
 							// Update explorer view
 							if exp, ok := a.explorerView.(*explorer.ExplorerView); ok {
@@ -105,7 +117,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "ctrl+c", "q":
 			if a.currentView == ViewExplorer {
-				return a, tea.Quit
+				return a, a.quit()
 			}
 		}
 
@@ -114,26 +126,55 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Handle connection messages
 	case ConnectRequestMsg:
+		// Tabs of the same connection share its pool, each with a session
+		if conn, ok := a.connections.GetConnection(msg.Config.ID); ok {
+			return a, openSessionCmd(msg.Config, conn)
+		}
 		return a, a.connectCmd(msg)
 
 	case ConnectSuccessMsg:
+		// Another tab connected meanwhile; share its connection
+		if active, ok := a.connections.GetConnection(msg.ConnID); ok && active != msg.Connection {
+			saved, _ := a.connections.GetSaved(msg.ConnID)
+			stale := map[*connected.ConnectedView]db.Session{nil: msg.Session}
+			return a, tea.Batch(closeConnectionCmd(msg.Connection, stale), openSessionCmd(saved, active))
+		}
+
 		// Create new tab with connected view
 		connInfo := fmt.Sprintf("%s @ %s", msg.ConnID, "database")
 		tab := Tab{
-			ID:     msg.ConnID,
-			ConnID: msg.ConnID,
-			View:   connected.NewConnectedView(msg.ConnID, connInfo),
+			ID:      msg.ConnID,
+			ConnID:  msg.ConnID,
+			View:    connected.NewConnectedView(msg.ConnID, connInfo),
+			Session: msg.Session,
 		}
 		a.tabs = append(a.tabs, tab)
 		a.currentTabIdx = len(a.tabs) - 1
 		a.currentView = ViewConnected
 
-		// Load schemas
-		return a, a.loadSchemasCmd(msg.ConnID)
+		// Load schemas and, for a new connection, start checking it
+		if active, ok := a.connections.GetConnection(msg.ConnID); ok && active == msg.Connection {
+			return a, a.loadSchemasCmd(msg.ConnID)
+		}
+		generation, replaced := a.connections.AddConnection(msg.ConnID, msg.Connection)
+		return a, tea.Batch(
+			a.loadSchemasCmd(msg.ConnID),
+			heartbeatCmd(msg.ConnID, generation),
+			closeConnectionCmd(replaced, nil),
+		)
+
+	case HeartbeatMsg, PingResultMsg, ReconnectMsg, ReconnectedMsg:
+		return a, a.updateHealth(msg)
+
+	case connected.ListenerRequestMsg, ListenerOpenedMsg, ListenerDoneMsg, NotificationMsg:
+		return a, a.updateNotifications(msg)
 
 	case ConnectErrorMsg:
 		// Show error and go back to explorer
-		fmt.Printf("Connection error: %v\n", msg.Err)
+		debug.LogError(msg.Err, "app/connect")
+		if exp, ok := a.explorerView.(*explorer.ExplorerView); ok {
+			exp.SetError(msg.Err.Error())
+		}
 		a.currentView = ViewExplorer
 		return a, nil
 
@@ -152,23 +193,222 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case treeview.ExpandMsg:
+		// A schema was expanded in the browser of the current tab
+		if schema, ok := msg.Node.Data.(db.Schema); ok && a.currentTabIdx >= 0 && a.currentTabIdx < len(a.tabs) {
+			return a, a.loadTablesCmd(a.tabs[a.currentTabIdx].ConnID, schema.Name)
+		}
+
+	case TablesLoadedMsg:
+		for i := range a.tabs {
+			tab := &a.tabs[i]
+			if tab.ConnID != msg.ConnID {
+				continue
+			}
+			if msg.Err != nil {
+				debug.LogError(msg.Err, "app/load_tables")
+				tab.View.StatusBar.SetError("Failed to load " + msg.Schema + ": " + msg.Err.Error())
+				tab.View.Browser.SchemaLoadFailed(msg.Schema)
+			} else {
+				tab.View.Browser.SetSchemaTables(msg.Schema, msg.Tables)
+			}
+		}
+
+	case TableInfoMsg:
+		for i := range a.tabs {
+			if a.tabs[i].ConnID == msg.ConnID {
+				a.tabs[i].View.Inspector.SetInfo(msg.Schema, msg.Table, msg.Info, msg.Err)
+			}
+		}
+
+	case DDLMsg:
+		if a.currentTabIdx >= 0 && a.currentTabIdx < len(a.tabs) {
+			tab := &a.tabs[a.currentTabIdx]
+			if tab.ConnID != msg.ConnID {
+				break
+			}
+			switch {
+			case msg.Err != nil:
+				tab.View.StatusBar.SetError(msg.Err.Error())
+			case msg.Copy:
+				if err := connected.CopyToClipboard(msg.DDL); err != nil {
+					tab.View.StatusBar.SetError(err.Error())
+				} else {
+					tab.View.StatusBar.SetInfo("Copied DDL of " + msg.Object.Name)
+				}
+			default:
+				tab.View.Editor.SetContent(msg.DDL)
+				tab.View.FocusedPane = connected.PaneEditor
+			}
+		}
+
+	case PlanMsg:
+		if a.currentTabIdx >= 0 && a.currentTabIdx < len(a.tabs) {
+			tab := &a.tabs[a.currentTabIdx]
+			if tab.ConnID == msg.ConnID {
+				tab.View.Plan.SetPlan(msg.Plan, msg.Err)
+				tab.View.QueryRunning = false
+				tab.View.StatusBar.SetQueryRunning(false)
+			}
+		}
+
+	case BackendsMsg:
+		for i := range a.tabs {
+			tab := &a.tabs[i]
+			if tab.ConnID != msg.ConnID || !a.showingSessions(tab, msg.Generation) {
+				continue
+			}
+			tab.View.Sessions.SetBackends(msg.Backends, msg.Err)
+			if msg.Err != nil {
+				debug.LogError(msg.Err, "app/list_backends")
+			}
+
+			// Refresh while the view is shown
+			connID, generation := msg.ConnID, msg.Generation
+			return a, tea.Tick(tab.View.Sessions.Interval(), func(time.Time) tea.Msg {
+				return BackendsTickMsg{ConnID: connID, Generation: generation}
+			})
+		}
+
+	case BackendsTickMsg:
+		for i := range a.tabs {
+			if a.tabs[i].ConnID == msg.ConnID && a.showingSessions(&a.tabs[i], msg.Generation) {
+				return a, a.listBackendsCmd(msg.ConnID, msg.Generation)
+			}
+		}
+
+	case BackendSignalledMsg:
+		for i := range a.tabs {
+			tab := &a.tabs[i]
+			if tab.ConnID != msg.ConnID {
+				continue
+			}
+			switch {
+			case msg.Err != nil:
+				tab.View.StatusBar.SetError(msg.Err.Error())
+			case msg.Terminate:
+				tab.View.StatusBar.SetInfo(fmt.Sprintf("Terminated backend %d", msg.PID))
+			default:
+				tab.View.StatusBar.SetInfo(fmt.Sprintf("Cancelled the query of backend %d", msg.PID))
+			}
+
+			// Show the outcome straight away, starting a new round of refreshes
+			switch tab.View.ResultsMode {
+			case connected.ResultsSessions:
+				return a, a.listBackendsCmd(tab.ConnID, tab.View.ShowSessions())
+			case connected.ResultsLocks:
+				tab.View.ShowLocks()
+				return a, a.listLockWaitsCmd(tab.ConnID)
+			}
+		}
+
+	case LockWaitsMsg:
+		for i := range a.tabs {
+			if a.tabs[i].ConnID == msg.ConnID {
+				a.tabs[i].View.Locks.SetLockWaits(msg.Waits, msg.Err)
+			}
+		}
+
 	case ExecuteQueryMsg:
 		return a, a.executeQueryCmd(msg)
 
 	case QueryResultMsg:
 		// Update results table
+		if a.currentView == ViewConnected && a.currentTabIdx >= 0 && a.currentTabIdx < len(a.tabs) {
+			tab := &a.tabs[a.currentTabIdx]
+			if tab.ConnID == msg.ConnID {
+				sets := msg.Result.ResultSets
+				if msg.Offset == 0 && len(sets) > 0 {
+					tab.View.Results.SetResultSets(sets)
+					tab.View.ResultsMode = connected.ResultsGrid
+				}
+				tab.Settings.Record(tab.View.TxStatus, sets)
+				if msg.Offset > 0 {
+					tab.View.Results.AddNotices(msg.SetIndex, msg.Notices)
+				}
+
+				tab.View.TxStatus = msg.TxStatus
+				tab.View.StatusBar.SetTxState(tab.View.ManualCommit, msg.TxStatus)
+
+				switch {
+				case msg.Err != nil:
+					if msg.Offset > 0 {
+						tab.View.Results.CloseCursor()
+					}
+					tab.View.StatusBar.SetError(scriptErrorText(msg.Err, sets))
+				case msg.Offset > 0:
+					tab.View.Results.AppendData(msg.SetIndex, msg.Result)
+					tab.View.StatusBar.SetQueryResult(tab.View.Results.RowCount(), msg.Elapsed)
+				case len(sets) > 1:
+					tab.View.StatusBar.SetExecResult(fmt.Sprintf("%d statements", len(sets)), msg.Elapsed)
+				case len(sets) == 1 && sets[0].Columns == nil:
+					tab.View.StatusBar.SetExecResult(sets[0].CommandTag, msg.Elapsed)
+				case len(sets) == 1:
+					tab.View.StatusBar.SetQueryResult(len(sets[0].Rows), msg.Elapsed)
+				}
+				tab.View.QueryRunning = false
+			}
+		}
+
+	case ExportCSVMsg:
+		return a, a.exportCSVCmd(msg)
+
+	case TxControlMsg:
+		return a, a.txControlCmd(msg)
+
+	case TxDoneMsg:
 		if a.currentView == ViewConnected && a.currentTabIdx >= 0 && a.currentTabIdx < len(a.tabs) {
 			tab := &a.tabs[a.currentTabIdx]
 			if tab.ConnID == msg.ConnID {
 				if msg.Err != nil {
 					tab.View.StatusBar.SetError(msg.Err.Error())
 				} else {
-					tab.View.Results.SetData(msg.Result)
-					tab.View.StatusBar.SetQueryResult(msg.Result.RowCount, msg.Elapsed)
+					tab.View.StatusBar.SetExecResult(msg.CommandTag, msg.Elapsed)
+				}
+				tab.Settings.EndTx(tab.View.TxStatus, msg.TxStatus, msg.SQL, msg.Err)
+				tab.View.TxStatus = msg.TxStatus
+				tab.View.StatusBar.SetTxState(tab.View.ManualCommit, msg.TxStatus)
+				tab.View.QueryRunning = false
+			}
+		}
+
+	case CloseTabMsg:
+		return a, a.closeTab(msg.Index)
+
+	case ExportDoneMsg:
+		if a.currentView == ViewConnected && a.currentTabIdx >= 0 && a.currentTabIdx < len(a.tabs) {
+			tab := &a.tabs[a.currentTabIdx]
+			if tab.ConnID == msg.ConnID {
+				if msg.Err != nil {
+					tab.View.StatusBar.SetError(fmt.Sprintf("Export failed after %d rows: %v", msg.Rows, msg.Err))
+				} else {
+					tab.View.StatusBar.SetInfo(fmt.Sprintf("Exported %d rows to %s in %v", msg.Rows, msg.FilePath, msg.Elapsed))
 				}
 				tab.View.QueryRunning = false
 			}
 		}
+
+	case LoadMoreResultsMsg:
+		// Fetch the next page of the last query
+		if a.currentView == ViewConnected && a.currentTabIdx >= 0 && a.currentTabIdx < len(a.tabs) {
+			tab := &a.tabs[a.currentTabIdx]
+			if tab.ConnID != msg.ConnID || tab.View.QueryRunning || !tab.View.Results.HasMore() {
+				return a, nil
+			}
+			if !tab.View.Results.CanLoadMore() {
+				tab.View.StatusBar.SetInfo("The cursor of these results is closed; run the query again to see more rows")
+				return a, nil
+			}
+			tab.View.QueryRunning = true
+			tab.View.StatusBar.SetQueryRunning(true)
+			index, sql := tab.View.Results.ActiveSet()
+			return a, a.executeQueryCmd(ExecuteQueryMsg{
+				ConnID:   tab.ConnID,
+				SQL:      sql,
+				Offset:   tab.View.Results.RowCount(),
+				SetIndex: index,
+			})
+		}
 	}
 
 	return a, nil
@@ -233,6 +473,17 @@ func (a *App) updateExplorer(msg tea.Msg) (tea.Model, tea.Cmd) {
 							Username: conn.Username,
 							Password: conn.Password,
 							Database: conn.Database,
+							Params:   conn.Params,
+							DSN:      conn.DSN,
+							TLS:      conn.TLS,
+							SSH:      conn.SSH,
+							Timeout:  conn.Timeout,
+
+							ConnectTimeout:   conn.ConnectTimeout,
+							StatementTimeout: conn.StatementTimeout,
+							LockTimeout:      conn.LockTimeout,
+							MaxConns:         conn.MaxConns,
+							MinConns:         conn.MinConns,
 						},
 					}
 				}
@@ -297,7 +548,7 @@ func (a *App) updateConnected(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch keyMsg.String() {
 		case "ctrl+c":
 			// Handle ctrl+c early to allow quitting
-			return a, tea.Quit
+			return a, a.quit()
 		case "ctrl+enter", "alt+enter":
 			// Execute query using Ctrl+Enter or Alt+Enter
 			debug.Logf("Executing query with key: %s", keyMsg.String())
@@ -305,24 +556,97 @@ func (a *App) updateConnected(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if sql != "" {
 				tab.View.QueryRunning = true
 				tab.View.StatusBar.SetQueryRunning(true)
+				continueOnError := tab.View.ContinueOnError
+				manualCommit := tab.View.ManualCommit
 				return a, func() tea.Msg {
 					return ExecuteQueryMsg{
-						ConnID: tab.ConnID,
-						SQL:    sql,
-						Offset: 0,
+						ConnID:          tab.ConnID,
+						SQL:             sql,
+						Offset:          0,
+						ContinueOnError: continueOnError,
+						ManualCommit:    manualCommit,
 					}
 				}
 			}
 			return a, nil
-		case "ctrl+w":
-			// Close current tab
-			a.tabs = append(a.tabs[:a.currentTabIdx], a.tabs[a.currentTabIdx+1:]...)
-			if len(a.tabs) == 0 {
-				a.currentView = ViewExplorer
-			} else if a.currentTabIdx >= len(a.tabs) {
-				a.currentTabIdx = len(a.tabs) - 1
+		case "ctrl+o":
+			// Toggle whether scripts stop at the first failing statement
+			tab.View.ContinueOnError = !tab.View.ContinueOnError
+			if tab.View.ContinueOnError {
+				tab.View.StatusBar.SetInfo("Scripts continue after errors")
+			} else {
+				tab.View.StatusBar.SetInfo("Scripts stop at the first error")
 			}
 			return a, nil
+		case "ctrl+l":
+			// Load next page of results
+			return a, func() tea.Msg {
+				return LoadMoreResultsMsg{ConnID: tab.ConnID}
+			}
+
+		case "ctrl+e":
+			// Export the editor's query to CSV
+			sql := tab.View.Editor.GetContent()
+			if sql != "" && !tab.View.QueryRunning {
+				tab.View.QueryRunning = true
+				tab.View.StatusBar.SetQueryRunning(true)
+				filePath := fmt.Sprintf("gosqlit-export-%s.csv", time.Now().Format("20060102-150405"))
+				return a, func() tea.Msg {
+					return ExportCSVMsg{
+						ConnID:   tab.ConnID,
+						SQL:      sql,
+						FilePath: filePath,
+					}
+				}
+			}
+			return a, nil
+
+		case "f6":
+			// Toggle between auto-commit and manual commit
+			if !a.capabilities(tab.ConnID).Transactions {
+				tab.View.StatusBar.SetInfo("Transactions are not supported by this driver")
+				return a, nil
+			}
+			tab.View.ManualCommit = !tab.View.ManualCommit
+			tab.View.StatusBar.SetTxState(tab.View.ManualCommit, tab.View.TxStatus)
+			if tab.View.ManualCommit {
+				tab.View.StatusBar.SetInfo("Manual commit: statements run in a transaction until F7 commits")
+			} else {
+				tab.View.StatusBar.SetInfo("Auto-commit")
+			}
+			return a, nil
+
+		case "f7", "f8":
+			// Commit or roll back the open transaction
+			if tab.View.QueryRunning {
+				return a, nil
+			}
+			if tab.View.TxStatus == db.TxIdle {
+				tab.View.StatusBar.SetInfo("No transaction in progress")
+				return a, nil
+			}
+			sql := "COMMIT"
+			if keyMsg.String() == "f8" {
+				sql = "ROLLBACK"
+			}
+			tab.View.QueryRunning = true
+			tab.View.StatusBar.SetQueryRunning(true)
+			return a, func() tea.Msg {
+				return TxControlMsg{ConnID: tab.ConnID, SQL: sql}
+			}
+
+		case "ctrl+w":
+			// Close current tab, confirming if that discards a transaction
+			idx := a.currentTabIdx
+			if tab.View.TxStatus != db.TxIdle {
+				a.activeModal = modal.NewConfirm(
+					"Uncommitted transaction",
+					"This tab has an open transaction. Close it and roll back?",
+					func() tea.Msg { return CloseTabMsg{Index: idx} },
+				)
+				return a, nil
+			}
+			return a, a.closeTab(idx)
 
 		case "ctrl+t":
 			// New tab - switch to explorer
@@ -331,6 +655,10 @@ func (a *App) updateConnected(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "ctrl+k":
 			// Cancel query
+			if !a.capabilities(tab.ConnID).Cancel {
+				tab.View.StatusBar.SetInfo("Cancelling queries is not supported by this driver")
+				return a, nil
+			}
 			if tab.View.QueryRunning && tab.View.CancelFunc != nil {
 				tab.View.CancelFunc()
 				tab.View.QueryRunning = false
@@ -338,19 +666,121 @@ func (a *App) updateConnected(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return a, nil
 
+		case "f9", "f10":
+			// Show the plan of the editor's statement; F10 runs it with ANALYZE
+			if !a.capabilities(tab.ConnID).Explain {
+				tab.View.StatusBar.SetInfo("EXPLAIN is not supported by this driver")
+				return a, nil
+			}
+			if tab.View.QueryRunning {
+				return a, nil
+			}
+			statements := db.SplitStatements(tab.View.Editor.GetContent())
+			if len(statements) != 1 {
+				tab.View.StatusBar.SetInfo("EXPLAIN takes a single statement")
+				return a, nil
+			}
+			analyze := keyMsg.String() == "f10"
+			tab.View.Explain(analyze)
+			tab.View.QueryRunning = true
+			tab.View.StatusBar.SetQueryRunning(true)
+			return a, a.explainCmd(tab.ConnID, statements[0], analyze)
+
+		case "f2":
+			// Toggle the notifications received with LISTEN in place of the results
+			if tab.View.ResultsMode == connected.ResultsNotifications {
+				tab.View.ResultsMode = connected.ResultsGrid
+				return a, nil
+			}
+			if !a.capabilities(tab.ConnID).Notify {
+				tab.View.StatusBar.SetInfo("Notifications are not supported by this driver")
+				return a, nil
+			}
+			tab.View.ShowNotifications()
+			return a, nil
+
+		case "f3":
+			// Toggle the server's sessions in place of the results
+			if tab.View.ResultsMode == connected.ResultsSessions {
+				tab.View.ResultsMode = connected.ResultsGrid
+				return a, nil
+			}
+			if !a.capabilities(tab.ConnID).Activity {
+				tab.View.StatusBar.SetInfo("Listing sessions is not supported by this driver")
+				return a, nil
+			}
+			return a, a.listBackendsCmd(tab.ConnID, tab.View.ShowSessions())
+
+		case "f4":
+			// Toggle the blocking tree in place of the results
+			if tab.View.ResultsMode == connected.ResultsLocks {
+				tab.View.ResultsMode = connected.ResultsGrid
+				return a, nil
+			}
+			if !a.capabilities(tab.ConnID).Activity {
+				tab.View.StatusBar.SetInfo("Listing locks is not supported by this driver")
+				return a, nil
+			}
+			tab.View.ShowLocks()
+			return a, a.listLockWaitsCmd(tab.ConnID)
+
 		case "f5", "ctrl+r":
 			// Refresh schemas
 			tab.View.StatusBar.SetError("Refreshing schemas...")
 			return a, a.loadSchemasCmd(tab.ConnID)
 		}
 
+		// Act on the backend selected in the sessions view
+		if tab.View.FocusedPane == connected.PaneResults && tab.View.ResultsMode == connected.ResultsSessions {
+			switch keyMsg.String() {
+			case "r":
+				return a, a.listBackendsCmd(tab.ConnID, tab.View.ShowSessions())
+			case "c", "x":
+				if backend, ok := tab.View.Sessions.SelectedBackend(); ok {
+					a.confirmSignalBackend(tab.ConnID, backend, keyMsg.String() == "x")
+				}
+				return a, nil
+			}
+		}
+
+		// Act on the root blocker selected in the locks view
+		if tab.View.FocusedPane == connected.PaneResults && tab.View.ResultsMode == connected.ResultsLocks {
+			switch keyMsg.String() {
+			case "r":
+				tab.View.ShowLocks()
+				return a, a.listLockWaitsCmd(tab.ConnID)
+			case "c", "x":
+				blocker, ok := tab.View.Locks.SelectedBlocker()
+				if !ok {
+					tab.View.StatusBar.SetInfo("Select a root blocker")
+					return a, nil
+				}
+				a.confirmSignalBackend(tab.ConnID, blocker.Backend, keyMsg.String() == "x")
+				return a, nil
+			}
+		}
+
 		// Handle table selection in schema browser
 		if tab.View.FocusedPane == connected.PaneSchemaBrowser {
+			if keyMsg.String() == "d" {
+				// Describe the table, like psql's \d+
+				if schema, table, ok := tab.View.Browser.GetSelectedTable(); ok {
+					tab.View.Inspect(schema, table)
+					return a, a.describeTableCmd(tab.ConnID, schema, table)
+				}
+			}
+			if keyMsg.String() == "s" || keyMsg.String() == "y" {
+				// Show DDL in the editor, or copy it
+				if object, ok := tab.View.Browser.GetSelectedObject(); ok {
+					return a, a.objectDDLCmd(tab.ConnID, object, keyMsg.String() == "y")
+				}
+			}
 			if keyMsg.String() == "enter" {
 				if schema, table, ok := tab.View.Browser.GetSelectedTable(); ok {
-					// Auto-generate SELECT query with proper identifier quoting for PostgreSQL
+					// Auto-generate SELECT query with the driver's identifier quoting
 					// Quote identifiers to handle special characters, uppercase, and reserved words
-					sql := fmt.Sprintf(`SELECT * FROM "%s"."%s" LIMIT 100`, schema, table)
+					quote := a.quoteIdentFunc(tab.ConnID)
+					sql := fmt.Sprintf(`SELECT * FROM %s.%s LIMIT 100`, quote(schema), quote(table))
 					debug.Logf("Auto-generated query: %s", sql)
 					tab.View.Editor.SetContent(sql)
 					tab.View.FocusedPane = connected.PaneEditor
@@ -366,45 +796,136 @@ func (a *App) updateConnected(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return a, cmd
 }
 
+// quoteIdentFunc returns the identifier quoting for a connection's driver
+func (a *App) quoteIdentFunc(connID string) func(string) string {
+	driverName := "postgres"
+	if saved, ok := a.connections.GetSaved(connID); ok {
+		driverName = saved.Driver
+	}
+
+	driver, err := db.GetDriver(driverName)
+	if err != nil {
+		return func(name string) string {
+			return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+		}
+	}
+
+	return func(name string) string {
+		return db.QuoteIdentifier(driver, name)
+	}
+}
+
+// capabilities returns what a connection's driver supports
+func (a *App) capabilities(connID string) db.Capabilities {
+	if saved, ok := a.connections.GetSaved(connID); ok {
+		if driver, err := db.GetDriver(saved.Driver); err == nil {
+			return driver.Capabilities()
+		}
+	}
+	return db.Capabilities{}
+}
+
 // connectCmd initiates connection
 func (a *App) connectCmd(msg ConnectRequestMsg) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		// The connect timeout covers the SSH tunnel and the first session too
+		ctx, cancel := context.WithTimeout(context.Background(), connectTimeout(msg.Config))
+		defer cancel()
 
-		// Get driver
-		driver, err := db.GetDriver(msg.Config.Driver)
+		conn, err := openConnection(ctx, msg.Config)
 		if err != nil {
-			return ConnectErrorMsg{
-				ConnID: msg.Config.ID,
-				Err:    fmt.Errorf("driver not found: %w", err),
-			}
+			return ConnectErrorMsg{ConnID: msg.Config.ID, Err: err}
 		}
 
-		// Connect
-		conn, err := driver.Connect(ctx, db.ConnConfig{
-			Host:     msg.Config.Host,
-			Port:     msg.Config.Port,
-			Username: msg.Config.Username,
-			Password: msg.Config.Password,
-			Database: msg.Config.Database,
-		})
+		session, err := conn.OpenSession(ctx)
 		if err != nil {
+			conn.Close()
 			return ConnectErrorMsg{
 				ConnID: msg.Config.ID,
-				Err:    fmt.Errorf("connection failed: %w", err),
+				Err:    fmt.Errorf("failed to open session: %w", err),
 			}
 		}
 
-		// Store connection
-		a.connections.AddConnection(msg.Config.ID, conn)
-
 		return ConnectSuccessMsg{
 			ConnID:     msg.Config.ID,
 			Connection: conn,
+			Session:    session,
+		}
+	}
+}
+
+// openSessionCmd opens a session for a new tab on an active connection
+func openSessionCmd(saved SavedConnection, conn db.Connection) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), connectTimeout(saved))
+		defer cancel()
+
+		session, err := conn.OpenSession(ctx)
+		if err != nil {
+			return ConnectErrorMsg{
+				ConnID: saved.ID,
+				Err:    fmt.Errorf("failed to open session: %w", err),
+			}
+		}
+		return ConnectSuccessMsg{
+			ConnID:     saved.ID,
+			Connection: conn,
+			Session:    session,
 		}
 	}
 }
 
+// connectTimeout is how long connecting with saved settings may take
+func connectTimeout(saved SavedConnection) time.Duration {
+	if saved.ConnectTimeout > 0 {
+		return time.Duration(saved.ConnectTimeout) * time.Second
+	}
+	return db.DefaultConnectTimeout
+}
+
+// openConnection connects with saved settings
+func openConnection(ctx context.Context, saved SavedConnection) (db.Connection, error) {
+	// Get driver
+	driver, err := db.GetDriver(saved.Driver)
+	if err != nil {
+		return nil, fmt.Errorf("driver not found: %w", err)
+	}
+
+	// A saved URL takes the place of the discrete settings
+	connConfig := db.ConnConfig{
+		Host:     saved.Host,
+		Port:     saved.Port,
+		Username: saved.Username,
+		Password: saved.Password,
+		Database: saved.Database,
+		Params:   saved.Params,
+		TLS:      db.TLSConfig(saved.TLS),
+	}
+	if saved.DSN != "" {
+		if _, connConfig, err = db.ParseURL(saved.DSN); err != nil {
+			return nil, err
+		}
+	}
+	connConfig.SSH = db.SSHConfig(saved.SSH)
+	connConfig.ConnectTimeout = time.Duration(saved.ConnectTimeout) * time.Second
+	connConfig.StatementTimeout = time.Duration(saved.StatementTimeout) * time.Second
+	connConfig.LockTimeout = time.Duration(saved.LockTimeout) * time.Second
+	connConfig.MaxConns = saved.MaxConns
+	connConfig.MinConns = saved.MinConns
+
+	// Connect
+	conn, err := driver.Connect(ctx, connConfig)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+
+	// Sessions take the query timeout when opened
+	if saved.Timeout > 0 {
+		conn.SetTimeout(time.Duration(saved.Timeout) * time.Second)
+	}
+	return conn, nil
+}
+
 // loadSchemasCmd loads schemas for a connection
 func (a *App) loadSchemasCmd(connID string) tea.Cmd {
 	return func() tea.Msg {
@@ -427,8 +948,184 @@ func (a *App) loadSchemasCmd(connID string) tea.Cmd {
 	}
 }
 
+// loadTablesCmd loads the objects of one schema
+func (a *App) loadTablesCmd(connID, schema string) tea.Cmd {
+	return func() tea.Msg {
+		conn, ok := a.connections.GetConnection(connID)
+		if !ok {
+			return TablesLoadedMsg{
+				ConnID: connID,
+				Schema: schema,
+				Err:    fmt.Errorf("connection not found"),
+			}
+		}
+
+		tables, err := conn.ListTables(context.Background(), schema)
+		return TablesLoadedMsg{
+			ConnID: connID,
+			Schema: schema,
+			Tables: tables,
+			Err:    err,
+		}
+	}
+}
+
+// describeTableCmd loads a table's structure for the inspector
+func (a *App) describeTableCmd(connID, schema, table string) tea.Cmd {
+	return func() tea.Msg {
+		msg := TableInfoMsg{ConnID: connID, Schema: schema, Table: table}
+
+		conn, ok := a.connections.GetConnection(connID)
+		if !ok {
+			msg.Err = fmt.Errorf("connection not found")
+			return msg
+		}
+
+		msg.Info, msg.Err = conn.GetTableInfo(context.Background(), schema, table)
+		return msg
+	}
+}
+
+// objectDDLCmd loads the CREATE statements of a schema object
+func (a *App) objectDDLCmd(connID string, object db.Table, toClipboard bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := DDLMsg{ConnID: connID, Object: object, Copy: toClipboard}
+
+		conn, ok := a.connections.GetConnection(connID)
+		if !ok {
+			msg.Err = fmt.Errorf("connection not found")
+			return msg
+		}
+		generator, ok := conn.(db.DDLGenerator)
+		if !ok {
+			msg.Err = fmt.Errorf("showing DDL is not supported by this driver")
+			return msg
+		}
+
+		msg.DDL, msg.Err = generator.ObjectDDL(context.Background(), object)
+		return msg
+	}
+}
+
+// explainCmd fetches the plan of a statement on the current tab's session
+func (a *App) explainCmd(connID, sql string, analyze bool) tea.Cmd {
+	session := a.runOnSession()
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Store cancel func in tab for Ctrl+K cancellation
+		if a.currentTabIdx >= 0 && a.currentTabIdx < len(a.tabs) {
+			a.tabs[a.currentTabIdx].View.CancelFunc = cancel
+		}
+
+		explainer, ok := session.(db.Explainer)
+		if !ok {
+			return PlanMsg{ConnID: connID, Err: fmt.Errorf("EXPLAIN is not supported by this driver")}
+		}
+
+		plan, err := explainer.Explain(ctx, sql, analyze)
+		return PlanMsg{ConnID: connID, Plan: plan, Err: err}
+	}
+}
+
+// showingSessions reports whether tab's sessions view is shown and still
+// on round generation of its refreshes
+func (a *App) showingSessions(tab *Tab, generation int) bool {
+	return tab.View.ResultsMode == connected.ResultsSessions &&
+		tab.View.Sessions.Generation() == generation
+}
+
+// listBackendsCmd lists the server's backends for the sessions view
+func (a *App) listBackendsCmd(connID string, generation int) tea.Cmd {
+	return func() tea.Msg {
+		msg := BackendsMsg{ConnID: connID, Generation: generation}
+
+		monitor, err := a.activityMonitor(connID)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+
+		msg.Backends, msg.Err = monitor.ListBackends(context.Background())
+		return msg
+	}
+}
+
+// listLockWaitsCmd lists lock waits for the locks view
+func (a *App) listLockWaitsCmd(connID string) tea.Cmd {
+	return func() tea.Msg {
+		msg := LockWaitsMsg{ConnID: connID}
+
+		conn, ok := a.connections.GetConnection(connID)
+		if !ok {
+			msg.Err = fmt.Errorf("connection not found")
+			return msg
+		}
+		monitor, ok := conn.(db.LockMonitor)
+		if !ok {
+			msg.Err = fmt.Errorf("listing locks is not supported by this driver")
+			return msg
+		}
+
+		msg.Waits, msg.Err = monitor.ListLockWaits(context.Background())
+		return msg
+	}
+}
+
+// confirmSignalBackend asks before cancelling the query of a backend, or
+// terminating it
+func (a *App) confirmSignalBackend(connID string, backend db.Backend, terminate bool) {
+	who := fmt.Sprintf("backend %d (%s, %s)", backend.PID, backend.User, backend.Application)
+	title, message := "Cancel query", "Cancel the query running on "+who+"?"
+	if terminate {
+		title = "Terminate backend"
+		message = "Terminate " + who + "? Its connection is closed and its transaction rolled back."
+	}
+	a.activeModal = modal.NewConfirm(title, message, a.signalBackendCmd(connID, backend.PID, terminate))
+}
+
+// signalBackendCmd cancels the query of a backend, or terminates it
+func (a *App) signalBackendCmd(connID string, pid int, terminate bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := BackendSignalledMsg{ConnID: connID, PID: pid, Terminate: terminate}
+
+		monitor, err := a.activityMonitor(connID)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+
+		if terminate {
+			msg.Err = monitor.TerminateBackend(context.Background(), pid)
+		} else {
+			msg.Err = monitor.CancelBackend(context.Background(), pid)
+		}
+		return msg
+	}
+}
+
+// activityMonitor returns a connection's activity monitor
+func (a *App) activityMonitor(connID string) (db.ActivityMonitor, error) {
+	conn, ok := a.connections.GetConnection(connID)
+	if !ok {
+		return nil, fmt.Errorf("connection not found")
+	}
+	monitor, ok := conn.(db.ActivityMonitor)
+	if !ok {
+		return nil, fmt.Errorf("listing sessions is not supported by this driver")
+	}
+	return monitor, nil
+}
+
 // executeQueryCmd executes SQL query with cancellation support
 func (a *App) executeQueryCmd(msg ExecuteQueryMsg) tea.Cmd {
+	session := a.currentSession()
+	if msg.Offset == 0 {
+		session = a.runOnSession()
+	}
+
 	return func() tea.Msg {
 		start := time.Now()
 		debug.Logf("executeQueryCmd started | ConnID: %s | SQL length: %d | Offset: %d",
@@ -444,18 +1141,48 @@ func (a *App) executeQueryCmd(msg ExecuteQueryMsg) tea.Cmd {
 			debug.Logf("Cancel func stored for tab %d", a.currentTabIdx)
 		}
 
-		conn, ok := a.connections.GetConnection(msg.ConnID)
-		if !ok {
-			debug.Logf("Connection not found for ID: %s", msg.ConnID)
+		if session == nil {
+			debug.Logf("Session not found for ID: %s", msg.ConnID)
 			return QueryResultMsg{
 				ConnID: msg.ConnID,
 				Err:    fmt.Errorf("connection not found"),
 			}
 		}
 
-		debug.Logf("Executing query on database...")
-		result, err := conn.Query(ctx, msg.SQL, 100, 0)
+		// Run the editor content as a script, one result set per statement
+		if msg.Offset == 0 {
+			statements := db.SplitStatements(msg.SQL)
+			if len(statements) == 0 {
+				statements = []string{msg.SQL}
+			}
+
+			debug.Logf("Executing %d statements on database...", len(statements))
+			sets, err := db.RunScript(ctx, session, statements, db.ScriptOptions{
+				Limit:           queryPageSize,
+				ContinueOnError: msg.ContinueOnError,
+				ManualCommit:    msg.ManualCommit,
+			})
+			elapsed := time.Since(start)
+
+			if err != nil {
+				debug.Logf("Script failed | elapsed: %v | error: %v", elapsed, err)
+			} else {
+				debug.Logf("Script succeeded | elapsed: %v | statements: %d", elapsed, len(sets))
+			}
+
+			return QueryResultMsg{
+				ConnID:   msg.ConnID,
+				Result:   db.QueryResult{ResultSets: sets},
+				Err:      err,
+				Elapsed:  elapsed,
+				TxStatus: session.TxStatus(),
+			}
+		}
+
+		debug.Logf("Fetching more rows from database...")
+		result, err := session.Query(ctx, msg.SQL, queryPageSize, msg.Offset)
 		elapsed := time.Since(start)
+		notices := db.TakeNotices(session)
 
 		if err != nil {
 			debug.Logf("Query failed | elapsed: %v | error: %v", elapsed, err)
@@ -465,10 +1192,183 @@ func (a *App) executeQueryCmd(msg ExecuteQueryMsg) tea.Cmd {
 		}
 
 		return QueryResultMsg{
-			ConnID:  msg.ConnID,
-			Result:  result,
-			Err:     err,
-			Elapsed: elapsed,
+			ConnID:   msg.ConnID,
+			Result:   result,
+			Err:      err,
+			Elapsed:  elapsed,
+			Offset:   msg.Offset,
+			SetIndex: msg.SetIndex,
+			Notices:  notices,
+			TxStatus: session.TxStatus(),
+		}
+	}
+}
+
+// txControlCmd commits or rolls back the current tab's transaction
+func (a *App) txControlCmd(msg TxControlMsg) tea.Cmd {
+	session := a.runOnSession()
+
+	return func() tea.Msg {
+		if session == nil {
+			return TxDoneMsg{ConnID: msg.ConnID, Err: fmt.Errorf("connection not found")}
+		}
+
+		start := time.Now()
+		result, err := session.Exec(context.Background(), msg.SQL)
+		debug.Logf("Transaction control %s | tag: %s | error: %v", msg.SQL, result.CommandTag, err)
+
+		return TxDoneMsg{
+			ConnID:     msg.ConnID,
+			SQL:        msg.SQL,
+			CommandTag: result.CommandTag,
+			Err:        err,
+			Elapsed:    time.Since(start),
+			TxStatus:   session.TxStatus(),
+		}
+	}
+}
+
+// currentSession returns the session of the active tab
+func (a *App) currentSession() db.Session {
+	if a.currentTabIdx >= 0 && a.currentTabIdx < len(a.tabs) {
+		return a.tabs[a.currentTabIdx].Session
+	}
+	return nil
+}
+
+// runOnSession returns the session of the active tab for a statement other
+// than a load more, which closes the cursor of the shown results
+func (a *App) runOnSession() db.Session {
+	if a.currentTabIdx >= 0 && a.currentTabIdx < len(a.tabs) {
+		a.tabs[a.currentTabIdx].View.Results.CloseCursor()
+	}
+	return a.currentSession()
+}
+
+// closeTab removes a tab, closing its session and listener in the
+// background, and its connection too if no other tab uses it. Any open
+// transaction is rolled back.
+func (a *App) closeTab(idx int) tea.Cmd {
+	if idx < 0 || idx >= len(a.tabs) {
+		return nil
+	}
+	tab := a.tabs[idx]
+
+	a.tabs = append(a.tabs[:idx], a.tabs[idx+1:]...)
+	if len(a.tabs) == 0 {
+		a.currentView = ViewExplorer
+	} else if a.currentTabIdx >= len(a.tabs) {
+		a.currentTabIdx = len(a.tabs) - 1
+	}
+
+	var conn db.Connection
+	if !a.connectionInUse(tab.ConnID) {
+		conn = a.connections.RemoveConnection(tab.ConnID)
+	}
+	sessions := map[*connected.ConnectedView]db.Session{tab.View: tab.Session}
+	return tea.Batch(closeConnectionCmd(conn, sessions), closeListenerCmd(tab.Listener))
+}
+
+// connectionInUse reports whether a tab is open on connection id
+func (a *App) connectionInUse(id string) bool {
+	for _, tab := range a.tabs {
+		if tab.ConnID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// quit exits, first asking for confirmation if a tab has an open transaction
+func (a *App) quit() tea.Cmd {
+	open := 0
+	for _, tab := range a.tabs {
+		if tab.View.TxStatus != db.TxIdle {
+			open++
+		}
+	}
+	if open == 0 {
+		return tea.Quit
+	}
+
+	a.activeModal = modal.NewConfirm(
+		"Uncommitted transactions",
+		fmt.Sprintf("%d tab(s) have an open transaction that will be rolled back. Quit anyway?", open),
+		tea.Quit,
+	)
+	return nil
+}
+
+// scriptErrorText describes a failed query, counting further failures when
+// a script continued after its first error
+func scriptErrorText(err error, sets []db.QueryResultSet) string {
+	failed := 0
+	for _, set := range sets {
+		if set.Err != nil {
+			failed++
+		}
+	}
+
+	if failed > 1 {
+		return fmt.Sprintf("%v (%d statements failed)", err, failed)
+	}
+	return err.Error()
+}
+
+// exportCSVCmd streams a query's full result into a CSV file
+func (a *App) exportCSVCmd(msg ExportCSVMsg) tea.Cmd {
+	session := a.runOnSession()
+
+	return func() tea.Msg {
+		start := time.Now()
+		debug.Logf("exportCSVCmd started | ConnID: %s | File: %s", msg.ConnID, msg.FilePath)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Store cancel func in tab for Ctrl+K cancellation
+		if a.currentTabIdx >= 0 && a.currentTabIdx < len(a.tabs) {
+			a.tabs[a.currentTabIdx].View.CancelFunc = cancel
+		}
+
+		if session == nil {
+			return ExportDoneMsg{
+				ConnID: msg.ConnID,
+				Err:    fmt.Errorf("connection not found"),
+			}
+		}
+
+		file, err := os.Create(msg.FilePath)
+		if err != nil {
+			return ExportDoneMsg{
+				ConnID: msg.ConnID,
+				Err:    fmt.Errorf("failed to create file: %w", err),
+			}
+		}
+		defer file.Close()
+
+		it, err := session.QueryRows(ctx, msg.SQL)
+		if err != nil {
+			return ExportDoneMsg{
+				ConnID: msg.ConnID,
+				Err:    err,
+			}
+		}
+		defer it.Close()
+
+		rows, err := export.WriteCSV(file, it)
+		if err == nil {
+			err = file.Close()
+		}
+
+		debug.Logf("exportCSVCmd finished | rows: %d | elapsed: %v | error: %v", rows, time.Since(start), err)
+
+		return ExportDoneMsg{
+			ConnID:   msg.ConnID,
+			FilePath: msg.FilePath,
+			Rows:     rows,
+			Err:      err,
+			Elapsed:  time.Since(start),
 		}
 	}
 }
@@ -486,6 +1386,17 @@ func ToSavedConnections(configs []config.SavedConnection) []SavedConnection {
 			Username: c.Username,
 			Password: c.Password,
 			Database: c.Database,
+			Params:   c.Params,
+			DSN:      c.DSN,
+			TLS:      c.TLS,
+			SSH:      c.SSH,
+			Timeout:  c.Timeout,
+
+			ConnectTimeout:   c.ConnectTimeout,
+			StatementTimeout: c.StatementTimeout,
+			LockTimeout:      c.LockTimeout,
+			MaxConns:         c.MaxConns,
+			MinConns:         c.MinConns,
 		}
 	}
 	return result
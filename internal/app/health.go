@@ -0,0 +1,211 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/imran-vz/gosqlit/internal/debug"
+	"github.com/imran-vz/gosqlit/internal/ui/connected"
+)
+
+// updateHealth handles heartbeat and reconnect messages. An active
+// connection is pinged every heartbeatInterval; once a ping fails it is
+// reconnected with backoff, and its tabs get new sessions with their
+// settings re-applied.
+func (a *App) updateHealth(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case HeartbeatMsg:
+		if a.connections.IsCurrent(msg.ConnID, msg.Generation) {
+			return a.pingCmd(msg.ConnID, msg.Generation)
+		}
+
+	case PingResultMsg:
+		if !a.connections.IsCurrent(msg.ConnID, msg.Generation) {
+			return nil
+		}
+		if msg.Err == nil {
+			return heartbeatCmd(msg.ConnID, msg.Generation)
+		}
+		debug.LogError(msg.Err, "app/heartbeat")
+		return a.connectionFailed(msg.ConnID, msg.Generation)
+
+	case ReconnectMsg:
+		if a.connections.IsCurrent(msg.ConnID, msg.Generation) {
+			return a.reconnectCmd(msg.ConnID, msg.Generation)
+		}
+
+	case ReconnectedMsg:
+		if msg.Err != nil {
+			debug.LogError(msg.Err, "app/reconnect")
+			if a.connections.IsCurrent(msg.ConnID, msg.Generation) {
+				return a.connectionFailed(msg.ConnID, msg.Generation)
+			}
+			return nil
+		}
+
+		// The connection was replaced while reconnecting
+		if !a.connections.IsCurrent(msg.ConnID, msg.Generation) {
+			return closeConnectionCmd(msg.Connection, msg.Sessions)
+		}
+
+		old := a.connections.ReplaceConnection(msg.ConnID, msg.Connection)
+		a.connections.MarkHealthy(msg.ConnID)
+
+		stale := make(map[*connected.ConnectedView]db.Session)
+		for i := range a.tabs {
+			tab := &a.tabs[i]
+			session, ok := msg.Sessions[tab.View]
+			if tab.ConnID != msg.ConnID || !ok {
+				continue
+			}
+			delete(msg.Sessions, tab.View)
+			stale[tab.View] = tab.Session
+			tab.Session = session
+			tab.View.Results.CloseCursor()
+			tab.Settings.Rollback()
+
+			tab.View.StatusBar.SetConnState(connected.ConnConnected, "")
+			switch {
+			case msg.SettingErrs[tab.View] != nil:
+				tab.View.StatusBar.SetError("Reconnected, but session settings failed: " + msg.SettingErrs[tab.View].Error())
+			case tab.View.TxStatus != db.TxIdle:
+				tab.View.StatusBar.SetError("Reconnected; the open transaction was rolled back")
+			default:
+				tab.View.StatusBar.SetInfo("Reconnected")
+			}
+			tab.View.TxStatus = db.TxIdle
+			tab.View.StatusBar.SetTxState(tab.View.ManualCommit, db.TxIdle)
+		}
+
+		// Sessions opened for tabs closed in the meantime go too
+		for view, session := range msg.Sessions {
+			stale[view] = session
+		}
+		return tea.Batch(heartbeatCmd(msg.ConnID, msg.Generation), closeConnectionCmd(old, stale))
+	}
+
+	return nil
+}
+
+// connectionFailed marks a connection as failing on its tabs and schedules
+// the next reconnect attempt
+func (a *App) connectionFailed(connID string, generation int) tea.Cmd {
+	state, delay := a.connections.MarkFailed(connID)
+	for i := range a.tabs {
+		if a.tabs[i].ConnID == connID {
+			a.tabs[i].View.StatusBar.SetConnState(state, fmt.Sprintf("retrying in %v", delay))
+		}
+	}
+
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return ReconnectMsg{ConnID: connID, Generation: generation}
+	})
+}
+
+// heartbeatCmd schedules the next ping of a connection
+func heartbeatCmd(connID string, generation int) tea.Cmd {
+	return tea.Tick(heartbeatInterval, func(time.Time) tea.Msg {
+		return HeartbeatMsg{ConnID: connID, Generation: generation}
+	})
+}
+
+// pingCmd checks that a connection still reaches the server
+func (a *App) pingCmd(connID string, generation int) tea.Cmd {
+	conn, ok := a.connections.GetConnection(connID)
+
+	return func() tea.Msg {
+		msg := PingResultMsg{ConnID: connID, Generation: generation}
+		if !ok {
+			msg.Err = fmt.Errorf("connection not found")
+			return msg
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), heartbeatTimeout)
+		defer cancel()
+
+		msg.Err = conn.Ping(ctx)
+		return msg
+	}
+}
+
+// reconnectCmd connects again with the saved settings and opens a session
+// for each tab of the connection, replaying the statements that changed its
+// settings
+func (a *App) reconnectCmd(connID string, generation int) tea.Cmd {
+	saved, ok := a.connections.GetSaved(connID)
+	settings := make(map[*connected.ConnectedView][]string)
+	for _, tab := range a.tabs {
+		if tab.ConnID == connID {
+			settings[tab.View] = tab.Settings.Statements()
+		}
+	}
+
+	return func() tea.Msg {
+		msg := ReconnectedMsg{ConnID: connID, Generation: generation}
+		if !ok {
+			msg.Err = fmt.Errorf("connection not found")
+			return msg
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), connectTimeout(saved))
+		defer cancel()
+
+		conn, err := openConnection(ctx, saved)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+
+		msg.Sessions = make(map[*connected.ConnectedView]db.Session)
+		msg.SettingErrs = make(map[*connected.ConnectedView]error)
+		for view, statements := range settings {
+			session, err := conn.OpenSession(ctx)
+			if err != nil {
+				for _, session := range msg.Sessions {
+					session.Close()
+				}
+				conn.Close()
+				return ReconnectedMsg{
+					ConnID:     connID,
+					Generation: generation,
+					Err:        fmt.Errorf("failed to open session: %w", err),
+				}
+			}
+			msg.Sessions[view] = session
+
+			for _, sql := range statements {
+				if _, err := session.Exec(ctx, sql); err != nil {
+					msg.SettingErrs[view] = err
+					break
+				}
+			}
+		}
+
+		msg.Connection = conn
+		return msg
+	}
+}
+
+// closeConnectionCmd closes sessions in the background, then conn, if any,
+// whose pool they were taken from
+func closeConnectionCmd(conn db.Connection, sessions map[*connected.ConnectedView]db.Session) tea.Cmd {
+	return func() tea.Msg {
+		for _, session := range sessions {
+			if session == nil {
+				continue
+			}
+			if err := session.Close(); err != nil {
+				debug.LogError(err, "app/close_session")
+			}
+		}
+		if conn != nil {
+			if err := conn.Close(); err != nil {
+				debug.LogError(err, "app/close_connection")
+			}
+		}
+		return nil
+	}
+}
@@ -3,7 +3,9 @@ package app
 import (
 	"time"
 
+	"github.com/imran-vz/gosqlit/internal/config"
 	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/imran-vz/gosqlit/internal/ui/connected"
 	"github.com/imran-vz/gosqlit/internal/ui/modal"
 )
 
@@ -15,6 +17,7 @@ type ConnectRequestMsg struct {
 type ConnectSuccessMsg struct {
 	ConnID     string
 	Connection db.Connection
+	Session    db.Session // for the new tab
 }
 
 type ConnectErrorMsg struct {
@@ -22,6 +25,39 @@ type ConnectErrorMsg struct {
 	Err    error
 }
 
+// Connection health. Generation is the ConnectionManager generation of the
+// connection the message is about; messages of an older one are dropped.
+
+// HeartbeatMsg is due when an active connection should be pinged
+type HeartbeatMsg struct {
+	ConnID     string
+	Generation int
+}
+
+// PingResultMsg carries the outcome of a heartbeat ping
+type PingResultMsg struct {
+	ConnID     string
+	Generation int
+	Err        error
+}
+
+// ReconnectMsg is due when a failed connection should be reconnected
+type ReconnectMsg struct {
+	ConnID     string
+	Generation int
+}
+
+// ReconnectedMsg carries a reconnected connection with a new session for
+// each of its tabs, keyed by the tab's view
+type ReconnectedMsg struct {
+	ConnID      string
+	Generation  int
+	Connection  db.Connection
+	Sessions    map[*connected.ConnectedView]db.Session
+	SettingErrs map[*connected.ConnectedView]error // settings that couldn't be re-applied
+	Err         error
+}
+
 type DisconnectMsg struct {
 	ConnID string
 }
@@ -37,17 +73,126 @@ type SchemasLoadedMsg struct {
 	Err     error
 }
 
-type ExecuteQueryMsg struct {
+// TablesLoadedMsg carries the objects of a schema expanded in the browser
+type TablesLoadedMsg struct {
 	ConnID string
-	SQL    string
-	Offset int
+	Schema string
+	Tables []db.Table
+	Err    error
 }
 
-type QueryResultMsg struct {
-	ConnID  string
-	Result  db.QueryResult
+// TableInfoMsg carries a table's structure for the inspector
+type TableInfoMsg struct {
+	ConnID string
+	Schema string
+	Table  string
+	Info   db.TableInfo
+	Err    error
+}
+
+// DDLMsg carries the CREATE statements of a schema object
+type DDLMsg struct {
+	ConnID string
+	Object db.Table
+	DDL    string
+	Err    error
+	Copy   bool // copy to the clipboard instead of opening in the editor
+}
+
+// PlanMsg carries the plan of the editor's statement
+type PlanMsg struct {
+	ConnID string
+	Plan   db.Plan
+	Err    error
+}
+
+// BackendsMsg carries the server's backends for the sessions view.
+// Generation is the sessions view round they were listed for.
+type BackendsMsg struct {
+	ConnID     string
+	Generation int
+	Backends   []db.Backend
+	Err        error
+}
+
+// BackendsTickMsg is due when the sessions view should be refreshed
+type BackendsTickMsg struct {
+	ConnID     string
+	Generation int
+}
+
+// LockWaitsMsg carries the lock waits for the locks view
+type LockWaitsMsg struct {
+	ConnID string
+	Waits  []db.LockWait
+	Err    error
+}
+
+// BackendSignalledMsg reports a cancelled or terminated backend
+type BackendSignalledMsg struct {
+	ConnID    string
+	PID       int
+	Terminate bool
+	Err       error
+}
+
+// ListenerOpenedMsg carries the listener opened for a tab's notifications
+// view, already listening on Channels
+type ListenerOpenedMsg struct {
+	View     *connected.ConnectedView
+	Listener db.Listener
+	Channels []string
+	Err      error
+}
+
+// ListenerDoneMsg reports a LISTEN, UNLISTEN or NOTIFY entered in a tab's
+// notifications view. On failure Request.Channels holds those done before.
+type ListenerDoneMsg struct {
+	View    *connected.ConnectedView
+	Request connected.ListenerRequestMsg
 	Err     error
-	Elapsed time.Duration
+}
+
+// NotificationMsg carries a notification received by a tab's listener
+type NotificationMsg struct {
+	Listener     db.Listener
+	Notification db.Notification
+	Err          error
+}
+
+type ExecuteQueryMsg struct {
+	ConnID          string
+	SQL             string
+	Offset          int
+	SetIndex        int  // result set a further page belongs to
+	ContinueOnError bool // keep running a script after a statement fails
+	ManualCommit    bool // begin a transaction before statements outside one
+}
+
+type QueryResultMsg struct {
+	ConnID   string
+	Result   db.QueryResult
+	Err      error
+	Elapsed  time.Duration
+	Offset   int // > 0 when Result is a further page of result set SetIndex
+	SetIndex int
+	Notices  []db.Notice // sent while fetching a further page
+	TxStatus db.TxStatus
+}
+
+// Transaction control
+type TxControlMsg struct {
+	ConnID string
+	SQL    string // COMMIT or ROLLBACK
+}
+
+type TxDoneMsg struct {
+	ConnID     string
+	SQL        string
+	CommandTag string
+	Err        error
+	Elapsed    time.Duration
+	TxStatus   db.TxStatus
 }
 
 type QueryCancelMsg struct {
@@ -59,9 +204,17 @@ type LoadMoreResultsMsg struct {
 }
 
 type ExportCSVMsg struct {
-	ConnID    string
-	FilePath  string
-	ExportAll bool
+	ConnID   string
+	SQL      string
+	FilePath string
+}
+
+type ExportDoneMsg struct {
+	ConnID   string
+	FilePath string
+	Rows     int
+	Err      error
+	Elapsed  time.Duration
 }
 
 // UI interactions
@@ -95,12 +248,23 @@ type SwitchTabMsg struct {
 
 // SavedConnection from config
 type SavedConnection struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Driver   string `json:"driver"`
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Database string `json:"database"`
+	ID       string             `json:"id"`
+	Name     string             `json:"name"`
+	Driver   string             `json:"driver"`
+	Host     string             `json:"host"`
+	Port     int                `json:"port"`
+	Username string             `json:"username"`
+	Password string             `json:"password"`
+	Database string             `json:"database"`
+	Params   map[string]string  `json:"params,omitempty"`
+	DSN      string             `json:"dsn,omitempty"`
+	TLS      config.TLSSettings `json:"tls"`
+	SSH      config.SSHSettings `json:"ssh"`
+	Timeout  int                `json:"timeout"` // query timeout, seconds
+
+	ConnectTimeout   int `json:"connect_timeout,omitempty"`
+	StatementTimeout int `json:"statement_timeout,omitempty"`
+	LockTimeout      int `json:"lock_timeout,omitempty"`
+	MaxConns         int `json:"max_conns,omitempty"`
+	MinConns         int `json:"min_conns,omitempty"`
 }
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/imran-vz/gosqlit/internal/debug"
+	"github.com/imran-vz/gosqlit/internal/ui/connected"
+)
+
+// updateNotifications handles the notifications view. A tab's listener is
+// opened on its first LISTEN and then waits for notifications, one at a
+// time, until it is closed with the tab or its connection is lost.
+func (a *App) updateNotifications(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case connected.ListenerRequestMsg:
+		if a.currentTabIdx < 0 || a.currentTabIdx >= len(a.tabs) {
+			return nil
+		}
+		tab := &a.tabs[a.currentTabIdx]
+
+		switch {
+		case msg.Action == connected.ListenerNotify:
+			return a.notifyCmd(tab.ConnID, tab.View, msg)
+		case tab.Listener != nil:
+			return listenerCmd(tab.Listener, tab.View, msg)
+		case msg.Action == connected.ListenerListen:
+			tab.View.Notifications.SetConnecting(true)
+			return a.openListenerCmd(tab.ConnID, tab.View, msg.Channels)
+		}
+		// Nothing to unlisten from without a listener
+		tab.View.Notifications.SetUnlistened(msg.Channels...)
+
+	case ListenerOpenedMsg:
+		tab := a.tabForView(msg.View)
+		if tab == nil {
+			return closeListenerCmd(msg.Listener)
+		}
+		tab.View.Notifications.SetConnecting(false)
+		if msg.Err != nil {
+			debug.LogError(msg.Err, "app/open_listener")
+			tab.View.Notifications.SetError(msg.Err)
+			return nil
+		}
+		tab.Listener = msg.Listener
+		tab.View.Notifications.SetListening(msg.Channels...)
+		return waitNotificationCmd(msg.Listener)
+
+	case ListenerDoneMsg:
+		tab := a.tabForView(msg.View)
+		if tab == nil {
+			return nil
+		}
+		if msg.Err != nil {
+			tab.View.Notifications.SetError(msg.Err)
+		}
+		switch msg.Request.Action {
+		case connected.ListenerListen:
+			tab.View.Notifications.SetListening(msg.Request.Channels...)
+		case connected.ListenerUnlisten:
+			tab.View.Notifications.SetUnlistened(msg.Request.Channels...)
+		case connected.ListenerNotify:
+			if msg.Err == nil {
+				tab.View.StatusBar.SetInfo("Sent notification on " + msg.Request.Channels[0])
+			}
+		}
+
+	case NotificationMsg:
+		var tab *Tab
+		for i := range a.tabs {
+			if a.tabs[i].Listener == msg.Listener {
+				tab = &a.tabs[i]
+			}
+		}
+		// The listener was closed with its tab
+		if tab == nil {
+			return nil
+		}
+
+		if msg.Err != nil {
+			debug.LogError(msg.Err, "app/wait_notification")
+			tab.Listener = nil
+			tab.View.Notifications.SetUnlistened("*")
+			tab.View.Notifications.SetError(fmt.Errorf("listener connection lost, listen again to reconnect: %w", msg.Err))
+			return closeListenerCmd(msg.Listener)
+		}
+		tab.View.Notifications.AddNotification(msg.Notification)
+		return waitNotificationCmd(msg.Listener)
+	}
+
+	return nil
+}
+
+// tabForView returns the tab showing view, or nil once it's closed
+func (a *App) tabForView(view *connected.ConnectedView) *Tab {
+	for i := range a.tabs {
+		if a.tabs[i].View == view {
+			return &a.tabs[i]
+		}
+	}
+	return nil
+}
+
+// openListenerCmd opens a listener on a connection of its own and listens
+// on channels
+func (a *App) openListenerCmd(connID string, view *connected.ConnectedView, channels []string) tea.Cmd {
+	conn, ok := a.connections.GetConnection(connID)
+	saved, _ := a.connections.GetSaved(connID)
+
+	return func() tea.Msg {
+		msg := ListenerOpenedMsg{View: view, Channels: channels}
+		if !ok {
+			msg.Err = fmt.Errorf("connection not found")
+			return msg
+		}
+		notifier, ok := conn.(db.Notifier)
+		if !ok {
+			msg.Err = fmt.Errorf("notifications are not supported by this driver")
+			return msg
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), connectTimeout(saved))
+		defer cancel()
+
+		listener, err := notifier.OpenListener(ctx)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		for _, channel := range channels {
+			if err := listener.Listen(ctx, channel); err != nil {
+				if err := listener.Close(); err != nil {
+					debug.LogError(err, "app/close_listener")
+				}
+				msg.Err = err
+				return msg
+			}
+		}
+
+		msg.Listener = listener
+		return msg
+	}
+}
+
+// listenerCmd runs a LISTEN or UNLISTEN on each of the request's channels
+func listenerCmd(listener db.Listener, view *connected.ConnectedView, request connected.ListenerRequestMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg := ListenerDoneMsg{View: view, Request: request}
+
+		run := listener.Listen
+		if request.Action == connected.ListenerUnlisten {
+			run = listener.Unlisten
+		}
+		for i, channel := range request.Channels {
+			if err := run(context.Background(), channel); err != nil {
+				msg.Request.Channels = request.Channels[:i]
+				msg.Err = err
+				break
+			}
+		}
+		return msg
+	}
+}
+
+// notifyCmd sends the request's payload on its channel
+func (a *App) notifyCmd(connID string, view *connected.ConnectedView, request connected.ListenerRequestMsg) tea.Cmd {
+	conn, ok := a.connections.GetConnection(connID)
+
+	return func() tea.Msg {
+		msg := ListenerDoneMsg{View: view, Request: request}
+		if !ok {
+			msg.Err = fmt.Errorf("connection not found")
+			return msg
+		}
+		notifier, ok := conn.(db.Notifier)
+		if !ok {
+			msg.Err = fmt.Errorf("notifications are not supported by this driver")
+			return msg
+		}
+
+		msg.Err = notifier.Notify(context.Background(), request.Channels[0], request.Payload)
+		return msg
+	}
+}
+
+// waitNotificationCmd waits for the listener's next notification
+func waitNotificationCmd(listener db.Listener) tea.Cmd {
+	return func() tea.Msg {
+		n, err := listener.WaitForNotification(context.Background())
+		return NotificationMsg{Listener: listener, Notification: n, Err: err}
+	}
+}
+
+// closeListenerCmd closes a listener in the background
+func closeListenerCmd(listener db.Listener) tea.Cmd {
+	if listener == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := listener.Close(); err != nil {
+			debug.LogError(err, "app/close_listener")
+		}
+		return nil
+	}
+}
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"time"
+
 	"github.com/imran-vz/gosqlit/internal/db"
 	"github.com/imran-vz/gosqlit/internal/ui/connected"
 	"github.com/imran-vz/gosqlit/internal/ui/modal"
@@ -33,10 +35,27 @@ type App struct {
 	explorerView interface{} // Will be ui/explorer.ExplorerView
 }
 
+// Heartbeat and reconnect timing
+const (
+	heartbeatInterval    = 15 * time.Second
+	heartbeatTimeout     = 5 * time.Second
+	maxReconnectDelay    = 30 * time.Second
+	reconnectsBeforeLost = 5 // failed attempts before a connection counts as lost
+)
+
 // ConnectionManager manages active connections
 type ConnectionManager struct {
-	saved  []SavedConnection
-	active map[string]db.Connection // connID → connection
+	saved      []SavedConnection
+	active     map[string]db.Connection // connID → connection
+	health     map[string]*connHealth   // connID → heartbeat state
+	generation int                      // of the last connection added
+}
+
+// connHealth is what the heartbeat knows about an active connection
+type connHealth struct {
+	generation int // bumped when the connection is added, stopping older heartbeats
+	state      connected.ConnState
+	failures   int // failed reconnect attempts in a row
 }
 
 // NewConnectionManager creates manager
@@ -44,6 +63,7 @@ func NewConnectionManager(saved []SavedConnection) *ConnectionManager {
 	return &ConnectionManager{
 		saved:  saved,
 		active: make(map[string]db.Connection),
+		health: make(map[string]*connHealth),
 	}
 }
 
@@ -53,22 +73,165 @@ func (cm *ConnectionManager) GetConnection(id string) (db.Connection, bool) {
 	return conn, ok
 }
 
-// AddConnection adds active connection
-func (cm *ConnectionManager) AddConnection(id string, conn db.Connection) {
+// GetSaved retrieves saved connection settings
+func (cm *ConnectionManager) GetSaved(id string) (SavedConnection, bool) {
+	for _, saved := range cm.saved {
+		if saved.ID == id {
+			return saved, true
+		}
+	}
+	return SavedConnection{}, false
+}
+
+// AddConnection adds active connection and returns the generation its
+// heartbeats belong to, along with the connection it replaces, if any, for
+// the caller to close
+func (cm *ConnectionManager) AddConnection(id string, conn db.Connection) (int, db.Connection) {
+	replaced := cm.active[id]
+	if replaced == conn {
+		replaced = nil
+	}
 	cm.active[id] = conn
+
+	// Generations are never reused, so a heartbeat of a removed connection
+	// can't pass for one of its successor
+	cm.generation++
+	cm.health[id] = &connHealth{generation: cm.generation}
+	return cm.generation, replaced
 }
 
-// RemoveConnection removes connection
-func (cm *ConnectionManager) RemoveConnection(id string) {
-	if conn, ok := cm.active[id]; ok {
-		conn.Close()
-		delete(cm.active, id)
+// ReplaceConnection swaps in a reconnected connection, keeping its
+// heartbeat, and returns the one it replaces
+func (cm *ConnectionManager) ReplaceConnection(id string, conn db.Connection) db.Connection {
+	old := cm.active[id]
+	cm.active[id] = conn
+	return old
+}
+
+// RemoveConnection removes connection, stopping its heartbeat, and returns
+// it for the caller to close
+func (cm *ConnectionManager) RemoveConnection(id string) db.Connection {
+	conn := cm.active[id]
+	delete(cm.active, id)
+	delete(cm.health, id)
+	return conn
+}
+
+// IsCurrent reports whether a heartbeat of generation still belongs to the
+// active connection id
+func (cm *ConnectionManager) IsCurrent(id string, generation int) bool {
+	health, ok := cm.health[id]
+	return ok && health.generation == generation
+}
+
+// MarkHealthy records a successful heartbeat or reconnect
+func (cm *ConnectionManager) MarkHealthy(id string) {
+	if health, ok := cm.health[id]; ok {
+		health.state = connected.ConnConnected
+		health.failures = 0
 	}
 }
 
+// MarkFailed records a failed heartbeat or reconnect attempt and returns
+// the connection's state and how long to wait before reconnecting. The wait
+// doubles with each failure, up to maxReconnectDelay.
+func (cm *ConnectionManager) MarkFailed(id string) (connected.ConnState, time.Duration) {
+	health, ok := cm.health[id]
+	if !ok {
+		return connected.ConnLost, maxReconnectDelay
+	}
+
+	// The cap is reached long before the shift could overflow
+	delay := maxReconnectDelay
+	if health.failures < 16 {
+		delay = min(time.Second<<health.failures, maxReconnectDelay)
+	}
+	health.failures++
+
+	health.state = connected.ConnReconnecting
+	if health.failures > reconnectsBeforeLost {
+		health.state = connected.ConnLost
+	}
+	return health.state, delay
+}
+
 // Tab represents connection tab
 type Tab struct {
-	ID     string
-	ConnID string
-	View   *connected.ConnectedView
+	ID      string
+	ConnID  string
+	View    *connected.ConnectedView
+	Session db.Session // connection held for the tab's queries and transaction
+
+	// Listener receives the notifications view's notifications; opened on
+	// the first LISTEN
+	Listener db.Listener
+
+	// Statements that changed session settings, such as SET search_path,
+	// run again on the new session after a reconnect
+	Settings SessionSettings
+}
+
+// SessionSettings records the statements that changed a session's settings,
+// one per setting. A setting changed inside a transaction only takes effect
+// once it commits.
+type SessionSettings struct {
+	applied []string
+	pending []string // in the open transaction
+}
+
+// Record records the statements of a script's result sets that changed a
+// setting, given the transaction state before the script ran
+func (ss *SessionSettings) Record(status db.TxStatus, sets []db.QueryResultSet) {
+	for _, set := range sets {
+		if set.Err == nil && db.IsSessionSetting(set.SQL) {
+			if set.TxStatus == db.TxIdle {
+				ss.applied = putSetting(ss.applied, set.SQL)
+			} else {
+				ss.pending = putSetting(ss.pending, set.SQL)
+			}
+		}
+		ss.EndTx(status, set.TxStatus, set.SQL, set.Err)
+		status = set.TxStatus
+	}
+}
+
+// EndTx applies the pending settings if sql committed the open transaction,
+// and drops them if it rolled back
+func (ss *SessionSettings) EndTx(before, after db.TxStatus, sql string, err error) {
+	if before == db.TxIdle || after != db.TxIdle {
+		return
+	}
+
+	// COMMIT of a failed transaction rolls it back
+	keyword := db.FirstKeyword(sql)
+	if err == nil && before == db.TxActive && (keyword == "COMMIT" || keyword == "END") {
+		for _, sql := range ss.pending {
+			ss.applied = putSetting(ss.applied, sql)
+		}
+	}
+	ss.pending = nil
+}
+
+// Rollback drops the pending settings, as when the session is lost
+func (ss *SessionSettings) Rollback() {
+	ss.pending = nil
+}
+
+// Statements returns the statements to replay on a new session
+func (ss *SessionSettings) Statements() []string {
+	return append([]string(nil), ss.applied...)
+}
+
+// putSetting adds sql to settings in place of an earlier statement for the
+// same setting. RESET ALL replaces them all.
+func putSetting(settings []string, sql string) []string {
+	name := db.SettingName(sql)
+
+	kept := settings[:0]
+	for _, s := range settings {
+		if name != "all" && db.SettingName(s) != name {
+			kept = append(kept, s)
+		}
+	}
+	return append(kept, sql)
 }
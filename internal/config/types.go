@@ -8,15 +8,42 @@ type Config struct {
 
 // SavedConnection holds connection details
 type SavedConnection struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Driver   string `json:"driver"`
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Database string `json:"database"`
-	Timeout  int    `json:"timeout"` // seconds, 0 = default
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Driver   string            `json:"driver"`
+	Host     string            `json:"host"`
+	Port     int               `json:"port"`
+	Username string            `json:"username"`
+	Password string            `json:"password"`
+	Database string            `json:"database"`
+	Params   map[string]string `json:"params,omitempty"` // driver parameters, e.g. sslmode
+	DSN      string            `json:"dsn,omitempty"`    // full connection URL, used instead of the fields above
+	TLS      TLSSettings       `json:"tls"`
+	SSH      SSHSettings       `json:"ssh"`
+	Timeout  int               `json:"timeout"` // query timeout, seconds, 0 = default
+
+	ConnectTimeout   int `json:"connect_timeout,omitempty"`   // seconds, 0 = default
+	StatementTimeout int `json:"statement_timeout,omitempty"` // seconds, server-side, 0 = none
+	LockTimeout      int `json:"lock_timeout,omitempty"`      // seconds, server-side, 0 = server default
+	MaxConns         int `json:"max_conns,omitempty"`         // 0 = default
+	MinConns         int `json:"min_conns,omitempty"`         // 0 = default
+}
+
+// TLSSettings holds a connection's TLS options
+type TLSSettings struct {
+	Mode       string `json:"mode,omitempty"` // libpq sslmode name, "" = driver default
+	CAFile     string `json:"ca_file,omitempty"`
+	CertFile   string `json:"cert_file,omitempty"`
+	KeyFile    string `json:"key_file,omitempty"`
+	ServerName string `json:"server_name,omitempty"`
+}
+
+// SSHSettings holds the SSH jump host a connection is tunneled through
+type SSHSettings struct {
+	Host           string `json:"host,omitempty"` // "" = no tunnel
+	User           string `json:"user,omitempty"`
+	KeyFile        string `json:"key_file,omitempty"` // "" = ssh-agent only
+	KnownHostsFile string `json:"known_hosts_file,omitempty"`
 }
 
 // EncryptedFile format on disk
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// Backend is a server process serving a client connection
+type Backend struct {
+	PID         int
+	Database    string
+	User        string
+	Application string
+	ClientAddr  string
+	State       string    // active, idle, idle in transaction, ...
+	WaitEvent   string    // e.g. "Lock: transactionid", empty when not waiting
+	QueryStart  time.Time // zero if no query has run
+	Query       string    // running query, or the last one when idle
+}
+
+// ActivityMonitor is implemented by connections that can list the server's
+// backends and cancel their queries or terminate them
+type ActivityMonitor interface {
+	ListBackends(ctx context.Context) ([]Backend, error)
+	CancelBackend(ctx context.Context, pid int) error
+	TerminateBackend(ctx context.Context, pid int) error
+}
+
+// LockWait is a backend taking part in lock contention: waiting for a lock,
+// holding one that others wait for, or both
+type LockWait struct {
+	Backend
+	BlockedBy []int     // backends holding or queued ahead for the lock it waits for
+	LockType  string    // relation, tuple, transactionid, ...; empty when not waiting
+	Mode      string    // lock mode waited for, e.g. RowExclusiveLock
+	Relation  string    // table the lock is on, if any
+	WaitStart time.Time // zero when not waiting
+}
+
+// LockMonitor is implemented by connections that can show which backends
+// block which
+type LockMonitor interface {
+	ListLockWaits(ctx context.Context) ([]LockWait, error)
+}
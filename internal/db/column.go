@@ -0,0 +1,82 @@
+package db
+
+import "strings"
+
+// ColumnKind is the broad category of a column's type, used to decide how
+// values are displayed
+type ColumnKind int
+
+const (
+	KindUnknown ColumnKind = iota
+	KindText
+	KindNumber
+	KindBool
+	KindTime
+	KindBinary
+	KindJSON
+	KindUUID
+	KindArray
+)
+
+// Column describes a result column
+type Column struct {
+	Name        string
+	TypeName    string // database type, e.g. "int4" or "VARCHAR"; "" if unknown
+	Kind        ColumnKind
+	NotNull     bool   // known to never hold NULL
+	Schema      string // source table's schema, when known
+	Table       string // source table, "" for computed columns
+	TableColumn string // column of Table the values come from
+}
+
+// Editable reports whether the column's values map back to a table column
+func (c Column) Editable() bool {
+	return c.Table != "" && c.TableColumn != ""
+}
+
+// ColumnNames returns the names of columns
+func ColumnNames(columns []Column) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// KindForTypeName guesses the kind of a database type from its name
+func KindForTypeName(typeName string) ColumnKind {
+	name := strings.ToUpper(typeName)
+	has := func(parts ...string) bool {
+		for _, part := range parts {
+			if strings.Contains(name, part) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case name == "":
+		return KindUnknown
+	case strings.HasPrefix(name, "_") || strings.HasSuffix(name, "[]"):
+		return KindArray
+	case has("POINT", "GEOMETRY", "POLYGON", "LINESTRING"):
+		return KindUnknown
+	case has("JSON"):
+		return KindJSON
+	case has("BOOL"):
+		return KindBool
+	case name == "UUID":
+		return KindUUID
+	case has("BLOB", "BINARY", "BYTEA"):
+		return KindBinary
+	case has("DATE", "TIME", "YEAR", "INTERVAL"):
+		return KindTime
+	case has("INT", "SERIAL", "DEC", "NUMERIC", "REAL", "FLOA", "DOUB", "MONEY"):
+		return KindNumber
+	case has("CHAR", "TEXT", "CLOB", "STRING", "ENUM", "SET", "NAME"):
+		return KindText
+	default:
+		return KindUnknown
+	}
+}
@@ -2,6 +2,8 @@ package db
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -10,13 +12,29 @@ type Driver interface {
 	Name() string
 	Connect(ctx context.Context, config ConnConfig) (Connection, error)
 	DefaultPort() int
-	RequiredFields() []string
+	Fields() []Field
+	Capabilities() Capabilities
+}
+
+// IdentifierQuoter is implemented by drivers that don't quote identifiers
+// with standard SQL double quotes
+type IdentifierQuoter interface {
+	QuoteIdentifier(name string) string
+}
+
+// QuoteIdentifier quotes a schema, table or column name for the driver
+func QuoteIdentifier(driver Driver, name string) string {
+	if quoter, ok := driver.(IdentifierQuoter); ok {
+		return quoter.QuoteIdentifier(name)
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
 }
 
 // Connection interface for active database connections
 type Connection interface {
-	Query(ctx context.Context, sql string, limit int, offset int) (QueryResult, error)
-	ListSchemas(ctx context.Context) ([]Schema, error)
+	Executor
+	OpenSession(ctx context.Context) (Session, error)
+	ListSchemas(ctx context.Context) ([]Schema, error) // Tables may be left nil, for ListTables
 	ListTables(ctx context.Context, schema string) ([]Table, error)
 	GetTableInfo(ctx context.Context, schema, table string) (TableInfo, error)
 	Ping(ctx context.Context) error
@@ -24,6 +42,23 @@ type Connection interface {
 	SetTimeout(duration time.Duration)
 }
 
+// DDLGenerator is implemented by connections that can show the statements
+// creating a schema object, e.g. a table's CREATE TABLE and its indexes
+type DDLGenerator interface {
+	ObjectDDL(ctx context.Context, object Table) (string, error)
+}
+
+// RowIterator streams query results one row at a time. Rows are read from
+// the server as Next is called, so callers can process results larger than
+// memory. Close must always be called.
+type RowIterator interface {
+	Columns() []Column
+	Next() bool
+	Values() ([]interface{}, error)
+	Err() error
+	Close() error
+}
+
 // ConnConfig holds connection configuration
 type ConnConfig struct {
 	Host     string
@@ -31,44 +66,175 @@ type ConnConfig struct {
 	Username string
 	Password string
 	Database string
+	Params   map[string]string // driver parameters, e.g. application_name
+	TLS      TLSConfig
+	SSH      SSHConfig // tunnel to reach Host through
+
+	ConnectTimeout   time.Duration // per new connection, 0 for DefaultConnectTimeout
+	StatementTimeout time.Duration // server-side limit per statement, 0 for none
+	LockTimeout      time.Duration // server-side limit on waiting for a lock, 0 for the server's
+	MaxConns         int           // pool size, 0 for DefaultMaxConns
+	MinConns         int           // connections kept open, 0 for DefaultMinConns
+}
+
+// Defaults for connections that don't set their own limits
+const (
+	DefaultQueryTimeout   = 30 * time.Second
+	DefaultConnectTimeout = 10 * time.Second
+	DefaultMaxConns       = 5
+	DefaultMinConns       = 1
+
+	// A tab's session holds one pooled connection for as long as it's
+	// open, and schema lookups and heartbeats need another
+	MinMaxConns = 2
+)
+
+// PoolSize returns the pool's max and min connections, with the defaults
+// for those left at 0
+func (c ConnConfig) PoolSize() (maxConns, minConns int, err error) {
+	if c.MaxConns < 0 || c.MinConns < 0 {
+		return 0, 0, fmt.Errorf("connection limits can't be negative (max %d, min %d)", c.MaxConns, c.MinConns)
+	}
+
+	maxConns, minConns = DefaultMaxConns, DefaultMinConns
+	if c.MaxConns > 0 {
+		maxConns = c.MaxConns
+	}
+	if c.MinConns > 0 {
+		minConns = c.MinConns
+	}
+	if maxConns < MinMaxConns {
+		return 0, 0, fmt.Errorf("max connections (%d) must be at least %d", maxConns, MinMaxConns)
+	}
+	if minConns > maxConns {
+		return 0, 0, fmt.Errorf("min connections (%d) exceeds max connections (%d)", minConns, maxConns)
+	}
+	return maxConns, minConns, nil
 }
 
 // QueryResult holds query results
 type QueryResult struct {
-	Columns    []string
+	Columns    []Column
 	Rows       [][]interface{}
 	RowCount   int
 	HasMore    bool
 	ResultSets []QueryResultSet
 }
 
-// QueryResultSet for multiple result sets
+// ExecResult holds the outcome of a statement that returns no rows
+type ExecResult struct {
+	CommandTag   string // e.g. "UPDATE 42", "CREATE TABLE"
+	RowsAffected int64
+}
+
+// QueryResultSet holds the outcome of one statement of a script
 type QueryResultSet struct {
-	Columns []string
-	Rows    [][]interface{}
+	SQL          string
+	Columns      []Column
+	Rows         [][]interface{}
+	HasMore      bool
+	CommandTag   string // set for statements that return no rows
+	RowsAffected int64
+	Notices      []Notice // sent by the server while the statement ran
+	TxStatus     TxStatus // the session's, once the statement ran
+	Err          error
 }
 
 // Schema represents database schema
 type Schema struct {
 	Name   string
-	Tables []Table
+	Tables []Table // nil when not loaded yet
 }
 
-// Table represents database table
+// Table represents a database object within a schema: a table, or any
+// other kind the driver lists
 type Table struct {
-	Name   string
-	Schema string
+	Name      string
+	Schema    string
+	Kind      ObjectKind
+	Signature string // argument types of functions and procedures, e.g. "(integer, text)"
+}
+
+// ObjectKind is the kind of a schema object. The zero value is a table, so
+// drivers that only list tables needn't set it.
+type ObjectKind int
+
+const (
+	ObjectTable ObjectKind = iota
+	ObjectView
+	ObjectMaterializedView
+	ObjectForeignTable
+	ObjectFunction
+	ObjectProcedure
+	ObjectSequence
+	ObjectEnum
+	ObjectType // composite, domain and range types
+)
+
+// ObjectKinds lists every object kind, in the order they're shown
+var ObjectKinds = []ObjectKind{
+	ObjectTable, ObjectView, ObjectMaterializedView, ObjectForeignTable,
+	ObjectFunction, ObjectProcedure, ObjectSequence, ObjectEnum, ObjectType,
+}
+
+// IsRelation reports whether objects of kind can be selected from
+func (k ObjectKind) IsRelation() bool {
+	switch k {
+	case ObjectTable, ObjectView, ObjectMaterializedView, ObjectForeignTable:
+		return true
+	}
+	return false
 }
 
-// TableInfo holds table metadata
+// TableInfo holds table metadata. Drivers fill in what they can; only
+// Columns is always set.
 type TableInfo struct {
-	Columns []ColumnInfo
+	Columns     []ColumnInfo
+	Comment     string
+	PrimaryKey  *Constraint // nil without one
+	Uniques     []Constraint
+	ForeignKeys []ForeignKey
+	Checks      []Constraint
+	Indexes     []Index
 }
 
 // ColumnInfo holds column metadata
 type ColumnInfo struct {
-	Name     string
-	Type     string
-	Nullable bool
-	Key      string // PRI, UNI, MUL, ""
+	Name      string
+	Type      string
+	Nullable  bool
+	Key       string // PRI, UNI, MUL (first column of a non-unique index), ""
+	Default   string // default expression, "" for none
+	Identity  string // ALWAYS or BY DEFAULT for identity columns
+	Generated string // expression of a generated column
+	Comment   string
+}
+
+// Constraint is a primary key, unique or check constraint
+type Constraint struct {
+	Name       string
+	Columns    []string
+	Definition string // as in a CREATE TABLE, e.g. "CHECK (price > 0)"
+}
+
+// ForeignKey is a constraint referencing another table's columns
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefSchema  string
+	RefTable   string
+	RefColumns []string
+	OnUpdate   string // referential action, e.g. CASCADE
+	OnDelete   string
+	Definition string
+}
+
+// Index describes an index on the table
+type Index struct {
+	Name       string
+	Columns    []string // key columns, or expressions
+	Unique     bool
+	Primary    bool
+	Method     string // access method, e.g. btree, gin
+	Definition string // CREATE INDEX statement
 }
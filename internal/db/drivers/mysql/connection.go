@@ -0,0 +1,296 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/imran-vz/gosqlit/internal/debug"
+)
+
+// Connection implements db.Connection for MySQL
+type Connection struct {
+	db      *sql.DB
+	timeout time.Duration
+	tunnel  *db.Tunnel // SSH tunnel connections are dialed through, if any
+}
+
+// Query executes SQL query, returning up to limit rows from offset, or
+// every row when limit <= 0. Each call runs the query again; only a
+// Session keeps its result open for the next page.
+func (c *Connection) Query(ctx context.Context, query string, limit int, offset int) (db.QueryResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	it, err := c.QueryRows(ctx, query)
+	if err != nil {
+		return db.QueryResult{}, err
+	}
+	defer it.Close()
+
+	if err := skipRows(it, offset); err != nil {
+		return db.QueryResult{}, err
+	}
+	return db.CollectRows(it, limit)
+}
+
+// QueryRows executes SQL and streams its rows, killing the query server-side
+// if ctx is cancelled. The connection timeout is not applied.
+func (c *Connection) QueryRows(ctx context.Context, query string) (db.RowIterator, error) {
+	conn, release, err := c.pinConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	it, err := newRowIterator(rows, release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	return it, nil
+}
+
+// Exec executes a statement that returns no rows, killing it server-side if
+// ctx is cancelled
+func (c *Connection) Exec(ctx context.Context, query string) (db.ExecResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, release, err := c.pinConn(ctx)
+	if err != nil {
+		return db.ExecResult{}, err
+	}
+	defer release()
+
+	res, err := conn.ExecContext(ctx, query)
+	if err != nil {
+		return db.ExecResult{}, fmt.Errorf("exec failed: %w", err)
+	}
+
+	affected, _ := res.RowsAffected()
+	return db.ExecResult{
+		CommandTag:   db.CommandTag(query, affected),
+		RowsAffected: affected,
+	}, nil
+}
+
+// ListSchemas returns all databases, without their tables
+func (c *Connection) ListSchemas(ctx context.Context) ([]db.Schema, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	query := `
+		SELECT schema_name
+		FROM information_schema.schemata
+		WHERE schema_name NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys')
+		ORDER BY schema_name
+	`
+
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+
+	defer rows.Close()
+
+	var schemas []db.Schema
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan schema: %w", err)
+		}
+		schemas = append(schemas, db.Schema{Name: name})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+
+	return schemas, nil
+}
+
+// ListTables returns tables and views in a database
+func (c *Connection) ListTables(ctx context.Context, schema string) ([]db.Table, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	query := `
+		SELECT table_name, table_type
+		FROM information_schema.tables
+		WHERE table_schema = ?
+		ORDER BY table_name
+	`
+
+	rows, err := c.db.QueryContext(ctx, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []db.Table
+	for rows.Next() {
+		var tableName, tableType string
+		if err := rows.Scan(&tableName, &tableType); err != nil {
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+
+		table := db.Table{
+			Name:   tableName,
+			Schema: schema,
+		}
+		if tableType == "VIEW" || tableType == "SYSTEM VIEW" {
+			table.Kind = db.ObjectView
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
+
+// GetTableInfo returns table metadata
+func (c *Connection) GetTableInfo(ctx context.Context, schema, table string) (db.TableInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// COLUMN_KEY is where the PRI/UNI/MUL convention comes from
+	query := `
+		SELECT column_name, column_type, is_nullable, column_key
+		FROM information_schema.columns
+		WHERE table_schema = ? AND table_name = ?
+		ORDER BY ordinal_position
+	`
+
+	rows, err := c.db.QueryContext(ctx, query, schema, table)
+	if err != nil {
+		return db.TableInfo{}, fmt.Errorf("failed to get table info: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []db.ColumnInfo
+	for rows.Next() {
+		var col db.ColumnInfo
+		var nullable string
+
+		if err := rows.Scan(&col.Name, &col.Type, &nullable, &col.Key); err != nil {
+			return db.TableInfo{}, fmt.Errorf("failed to scan column: %w", err)
+		}
+
+		col.Nullable = (nullable == "YES")
+
+		columns = append(columns, col)
+	}
+
+	if err := rows.Err(); err != nil {
+		return db.TableInfo{}, fmt.Errorf("rows error: %w", err)
+	}
+
+	return db.TableInfo{
+		Columns: columns,
+	}, nil
+}
+
+// Ping checks if connection is alive
+func (c *Connection) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	return c.db.PingContext(ctx)
+}
+
+// Close closes the connection
+func (c *Connection) Close() error {
+	err := c.db.Close()
+	c.tunnel.Close()
+	return err
+}
+
+// SetTimeout sets query timeout
+func (c *Connection) SetTimeout(duration time.Duration) {
+	c.timeout = duration
+}
+
+// pinConn takes a connection from the pool and watches ctx so the statement
+// run on it is killed on cancel. release must be called when done.
+func (c *Connection) pinConn(ctx context.Context) (*sql.Conn, func(), error) {
+	conn, threadID, err := c.reserveConn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stopWatch := c.watchCancel(ctx, threadID)
+	release := func() {
+		stopWatch()
+		conn.Close()
+	}
+
+	return conn, release, nil
+}
+
+// reserveConn takes a connection from the pool along with its thread id
+func (c *Connection) reserveConn(ctx context.Context) (*sql.Conn, uint64, error) {
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	var threadID uint64
+	if err := conn.QueryRowContext(ctx, "SELECT CONNECTION_ID()").Scan(&threadID); err != nil {
+		conn.Close()
+		return nil, 0, fmt.Errorf("failed to get connection id: %w", err)
+	}
+
+	return conn, threadID, nil
+}
+
+// watchCancel kills the statement running on threadID if ctx is done before
+// the returned stop func is called. The driver only drops its socket on
+// cancel, which leaves the query running on the server. stop waits for the
+// watcher so a late KILL can't hit whoever uses the thread next.
+func (c *Connection) watchCancel(ctx context.Context, threadID uint64) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+		}
+
+		killCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := c.db.ExecContext(killCtx, fmt.Sprintf("KILL QUERY %d", threadID)); err != nil {
+			debug.LogError(err, "mysql/kill_query")
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
+// isBinaryType reports whether values of the column type are raw bytes
+func isBinaryType(typeName string) bool {
+	switch typeName {
+	case "BINARY", "VARBINARY", "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "GEOMETRY", "BIT":
+		return true
+	default:
+		return false
+	}
+}
+
+// quoteIdent quotes a MySQL identifier
+func quoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
@@ -0,0 +1,367 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/server"
+	gmssql "github.com/dolthub/go-mysql-server/sql"
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/sirupsen/logrus"
+)
+
+func TestMain(m *testing.M) {
+	logrus.SetLevel(logrus.ErrorLevel)
+	os.Exit(m.Run())
+}
+
+// startServer runs an in-memory go-mysql-server on a free local port with
+// an empty "shop" database, and returns a config to connect to it
+func startServer(t *testing.T) db.ConnConfig {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	pro := memory.NewDBProvider(memory.NewDatabase("shop"))
+	engine := sqle.NewDefault(pro)
+	srv, err := server.NewServer(server.Config{Protocol: "tcp", Listener: listener}, engine, gmssql.NewContext, memory.NewSessionBuilder(pro), nil)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	go srv.Start()
+	t.Cleanup(func() { srv.Close() })
+
+	return db.ConnConfig{
+		Host:     "127.0.0.1",
+		Port:     listener.Addr().(*net.TCPAddr).Port,
+		Username: "root",
+		Database: "shop",
+	}
+}
+
+// connect connects to a fresh server and creates the tables of the tests
+func connect(t *testing.T) *Connection {
+	t.Helper()
+
+	ctx := context.Background()
+	conn, err := (&Driver{}).Connect(ctx, startServer(t))
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	for _, sql := range []string{
+		"CREATE TABLE customers (id INT PRIMARY KEY, email VARCHAR(100) UNIQUE, name VARCHAR(100))",
+		"CREATE TABLE orders (id INT PRIMARY KEY, customer_id INT NOT NULL, total DECIMAL(10,2), KEY idx_customer (customer_id))",
+		"CREATE VIEW big_orders AS SELECT * FROM orders WHERE total > 100",
+	} {
+		if _, err := conn.Exec(ctx, sql); err != nil {
+			t.Fatalf("Exec(%q) error = %v", sql, err)
+		}
+	}
+	return conn.(*Connection)
+}
+
+func TestPing(t *testing.T) {
+	conn := connect(t)
+
+	if err := conn.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+}
+
+func TestListSchemas(t *testing.T) {
+	conn := connect(t)
+
+	schemas, err := conn.ListSchemas(context.Background())
+	if err != nil {
+		t.Fatalf("ListSchemas() error = %v", err)
+	}
+
+	var names []string
+	for _, schema := range schemas {
+		names = append(names, schema.Name)
+	}
+	if want := []string{"shop"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ListSchemas() = %v, want %v", names, want)
+	}
+}
+
+func TestListTables(t *testing.T) {
+	conn := connect(t)
+
+	tables, err := conn.ListTables(context.Background(), "shop")
+	if err != nil {
+		t.Fatalf("ListTables() error = %v", err)
+	}
+
+	want := []db.Table{
+		{Name: "big_orders", Schema: "shop", Kind: db.ObjectView},
+		{Name: "customers", Schema: "shop", Kind: db.ObjectTable},
+		{Name: "orders", Schema: "shop", Kind: db.ObjectTable},
+	}
+	if !reflect.DeepEqual(tables, want) {
+		t.Errorf("ListTables() = %+v, want %+v", tables, want)
+	}
+}
+
+func TestGetTableInfo(t *testing.T) {
+	conn := connect(t)
+
+	tests := []struct {
+		table string
+		want  []db.ColumnInfo
+	}{
+		{
+			table: "customers",
+			want: []db.ColumnInfo{
+				{Name: "id", Type: "int", Key: "PRI"},
+				{Name: "email", Type: "varchar(100)", Nullable: true, Key: "UNI"},
+				{Name: "name", Type: "varchar(100)", Nullable: true},
+			},
+		},
+		{
+			table: "orders",
+			want: []db.ColumnInfo{
+				{Name: "id", Type: "int", Key: "PRI"},
+				{Name: "customer_id", Type: "int", Key: "MUL"},
+				{Name: "total", Type: "decimal(10,2)", Nullable: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			info, err := conn.GetTableInfo(context.Background(), "shop", tt.table)
+			if err != nil {
+				t.Fatalf("GetTableInfo() error = %v", err)
+			}
+			if !reflect.DeepEqual(info.Columns, tt.want) {
+				t.Errorf("GetTableInfo() columns = %+v, want %+v", info.Columns, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecCommandTags(t *testing.T) {
+	conn := connect(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		sql      string
+		tag      string
+		affected int64
+	}{
+		{"INSERT INTO customers VALUES (1, 'a@example.com', 'Ann'), (2, 'b@example.com', 'Bob')", "INSERT 2", 2},
+		{"UPDATE customers SET name = 'Anne' WHERE id = 1", "UPDATE 1", 1},
+		{"DELETE FROM customers WHERE id > 0", "DELETE 2", 2},
+		{"CREATE TABLE notes (id INT PRIMARY KEY)", "CREATE TABLE", 0},
+		{"DROP TABLE notes", "DROP TABLE", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			result, err := conn.Exec(ctx, tt.sql)
+			if err != nil {
+				t.Fatalf("Exec() error = %v", err)
+			}
+			if result.CommandTag != tt.tag || result.RowsAffected != tt.affected {
+				t.Errorf("Exec() = %q, %d rows; want %q, %d rows", result.CommandTag, result.RowsAffected, tt.tag, tt.affected)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	conn := connect(t)
+	ctx := context.Background()
+
+	if _, err := conn.Exec(ctx, "INSERT INTO customers VALUES (1, 'a@example.com', NULL), (2, 'b@example.com', 'Bob')"); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+
+	result, err := conn.Query(ctx, "SELECT id, name FROM customers ORDER BY id", 0, 0)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	var columns []string
+	for _, col := range result.Columns {
+		columns = append(columns, col.Name)
+	}
+	if want := []string{"id", "name"}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("Query() columns = %v, want %v", columns, want)
+	}
+	if len(result.Rows) != 2 {
+		t.Fatalf("Query() returned %d rows, want 2", len(result.Rows))
+	}
+	if result.Rows[0][1] != nil {
+		t.Errorf("Query() NULL name = %v, want nil", result.Rows[0][1])
+	}
+}
+
+func TestSessionQueryPages(t *testing.T) {
+	conn := connect(t)
+	ctx := context.Background()
+
+	if _, err := conn.Exec(ctx, "INSERT INTO customers VALUES (1, 'a@example.com', NULL), (2, 'b@example.com', NULL), (3, 'c@example.com', NULL)"); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+
+	session, err := conn.OpenSession(ctx)
+	if err != nil {
+		t.Fatalf("OpenSession() error = %v", err)
+	}
+	defer session.Close()
+
+	const query = "SELECT id FROM customers ORDER BY id"
+	ids := func(result db.QueryResult) string {
+		var ids []string
+		for _, row := range result.Rows {
+			ids = append(ids, fmt.Sprint(row[0]))
+		}
+		return strings.Join(ids, ",")
+	}
+
+	first, err := session.Query(ctx, query, 2, 0)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if ids(first) != "1,2" || !first.HasMore {
+		t.Errorf("first page = %s (more %v), want 1,2 with more", ids(first), first.HasMore)
+	}
+	if session.(*Session).page == nil {
+		t.Fatal("first page didn't keep the result open")
+	}
+
+	next, err := session.Query(ctx, query, 2, 2)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if ids(next) != "3" || next.HasMore {
+		t.Errorf("next page = %s (more %v), want 3 and no more", ids(next), next.HasMore)
+	}
+	if session.(*Session).page != nil {
+		t.Error("last page left the result open")
+	}
+
+	// Without an open result the query runs again from offset
+	again, err := session.Query(ctx, query, 2, 1)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if ids(again) != "2,3" || again.HasMore {
+		t.Errorf("page at offset 1 = %s (more %v), want 2,3 and no more", ids(again), again.HasMore)
+	}
+
+	limited, err := conn.Query(ctx, query, 1, 1)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if ids(limited) != "2" || !limited.HasMore {
+		t.Errorf("Connection page = %s (more %v), want 2 with more", ids(limited), limited.HasMore)
+	}
+}
+
+func TestCancelKillsQuery(t *testing.T) {
+	conn := connect(t)
+
+	// A session's query runs on its thread; cancelling its ctx must KILL
+	// QUERY on the server, not just drop the client's wait
+	session, err := conn.OpenSession(context.Background())
+	if err != nil {
+		t.Fatalf("OpenSession() error = %v", err)
+	}
+	defer session.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = session.Query(ctx, "SELECT SLEEP(10)", 0, 0)
+	if err == nil {
+		t.Fatal("Query() succeeded, want it cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Query() returned after %v, want it cancelled promptly", elapsed)
+	}
+
+	// The server mustn't still be running it
+	result, err := conn.Query(context.Background(), "SELECT info FROM information_schema.processlist WHERE info LIKE 'SELECT SLEEP%'", 0, 0)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(result.Rows) != 0 {
+		t.Errorf("SLEEP still running after cancel: %v", result.Rows)
+	}
+}
+
+func TestWatchCancelKillsThread(t *testing.T) {
+	conn := connect(t)
+
+	sqlConn, threadID, err := conn.reserveConn(context.Background())
+	if err != nil {
+		t.Fatalf("reserveConn() error = %v", err)
+	}
+	defer sqlConn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := conn.watchCancel(ctx, threadID)
+
+	// The query runs without ctx, so only the KILL QUERY can end it early
+	done := make(chan error, 1)
+	go func() {
+		var slept int
+		done <- sqlConn.QueryRowContext(context.Background(), "SELECT SLEEP(10)").Scan(&slept)
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("query still running after KILL QUERY")
+	}
+	stop()
+}
+
+func TestSessionCloseDiscardsConn(t *testing.T) {
+	conn := connect(t)
+	ctx := context.Background()
+
+	session, err := conn.OpenSession(ctx)
+	if err != nil {
+		t.Fatalf("OpenSession() error = %v", err)
+	}
+	if _, err := session.Exec(ctx, "SET @tab_var = 1"); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	threadID := session.(*Session).threadID
+	if err := session.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// The session's connection, and its variables, mustn't be reused
+	for i := 0; i < 3; i++ {
+		result, err := conn.Query(ctx, "SELECT CONNECTION_ID(), @tab_var", 0, 0)
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		row := result.Rows[0]
+		if fmt.Sprint(row[0]) == fmt.Sprint(threadID) || row[1] != nil {
+			t.Fatalf("pool reused the session's connection: %v", row)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// ObjectDDL returns the CREATE statement of a table or view, as SHOW
+// CREATE prints it
+func (c *Connection) ObjectDDL(ctx context.Context, object db.Table) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	name := quoteIdent(object.Schema) + "." + quoteIdent(object.Name)
+
+	var ddl string
+	var err error
+	switch object.Kind {
+	case db.ObjectTable:
+		var table string
+		err = c.db.QueryRowContext(ctx, "SHOW CREATE TABLE "+name).Scan(&table, &ddl)
+	case db.ObjectView:
+		var view, charset, collation string
+		err = c.db.QueryRowContext(ctx, "SHOW CREATE VIEW "+name).Scan(&view, &ddl, &charset, &collation)
+	default:
+		return "", fmt.Errorf("DDL is not supported for %s", object.Name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get DDL: %w", err)
+	}
+
+	return ddl + ";\n", nil
+}
@@ -0,0 +1,163 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// Driver implements db.Driver for MySQL and MariaDB
+type Driver struct{}
+
+// Name returns driver name
+func (d *Driver) Name() string {
+	return "mysql"
+}
+
+// DefaultPort returns default MySQL port
+func (d *Driver) DefaultPort() int {
+	return 3306
+}
+
+// Fields returns the connection settings MySQL takes
+func (d *Driver) Fields() []db.Field {
+	fields := []db.Field{
+		{Key: db.FieldHost, Label: "Host", Default: "localhost"},
+		{Key: db.FieldPort, Label: "Port", Type: db.FieldNumber, Default: strconv.Itoa(d.DefaultPort())},
+		{Key: db.FieldUsername, Label: "Username"},
+		{Key: db.FieldPassword, Label: "Password", Secret: true, Optional: true},
+		{Key: db.FieldDatabase, Label: "Database", Optional: true},
+		{Key: db.FieldParams, Label: "Parameters", Optional: true},
+	}
+	fields = append(fields, db.TLSFields("disable")...)
+	fields = append(fields, db.SSHFields()...)
+	return append(fields, db.LimitFields()...)
+}
+
+// Schemes returns the connection URL schemes MySQL accepts
+func (d *Driver) Schemes() []string {
+	return []string{"mysql", "mariadb"}
+}
+
+// Capabilities returns what MySQL supports. Its schemas are databases.
+func (d *Driver) Capabilities() db.Capabilities {
+	return db.Capabilities{
+		Transactions: true,
+		Cancel:       true,
+	}
+}
+
+// QuoteIdentifier quotes an identifier with backticks
+func (d *Driver) QuoteIdentifier(name string) string {
+	return quoteIdent(name)
+}
+
+// Connect establishes connection to MySQL
+func (d *Driver) Connect(ctx context.Context, config db.ConnConfig) (db.Connection, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = config.Username
+	cfg.Passwd = config.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	cfg.DBName = config.Database
+	cfg.ParseTime = true
+
+	// Parameters are driver options or system variables, as in a DSN
+	if len(config.Params) > 0 {
+		query := url.Values{}
+		for key, value := range config.Params {
+			query.Set(key, value)
+		}
+		dsn := cfg.FormatDSN()
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+
+		var err error
+		cfg, err = mysql.ParseDSN(dsn + sep + query.Encode())
+		if err != nil {
+			return nil, fmt.Errorf("invalid connection parameters: %w", err)
+		}
+	}
+
+	cfg.Timeout = db.DefaultConnectTimeout
+	if config.ConnectTimeout > 0 {
+		cfg.Timeout = config.ConnectTimeout
+	}
+
+	// Server-side limits are set as session variables on every connection.
+	// max_execution_time only limits SELECTs; MariaDB calls it
+	// max_statement_time, which can be set in the parameters instead.
+	if config.StatementTimeout > 0 || config.LockTimeout > 0 {
+		if cfg.Params == nil {
+			cfg.Params = make(map[string]string)
+		}
+		if config.StatementTimeout > 0 {
+			cfg.Params["max_execution_time"] = strconv.FormatInt(config.StatementTimeout.Milliseconds(), 10)
+		}
+		if config.LockTimeout > 0 {
+			// Whole seconds, at least one
+			lockSeconds := max(int64((config.LockTimeout+time.Second-1)/time.Second), 1)
+			cfg.Params["innodb_lock_wait_timeout"] = strconv.FormatInt(lockSeconds, 10)
+		}
+	}
+
+	maxConns, minConns, err := config.PoolSize()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyTLS(cfg, config.TLS, config.Host); err != nil {
+		return nil, err
+	}
+
+	var tunnel *db.Tunnel
+	if config.SSH.Enabled() {
+		var err error
+		tunnel, err = db.OpenTunnel(ctx, config.SSH)
+		if err != nil {
+			return nil, err
+		}
+		cfg.DialFunc = tunnel.Dial
+	}
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		tunnel.Close()
+		return nil, fmt.Errorf("failed to parse connection config: %w", err)
+	}
+
+	sqlDB := sql.OpenDB(connector)
+
+	// Set pool settings. database/sql has no minimum, so keep that many idle.
+	sqlDB.SetMaxOpenConns(maxConns)
+	sqlDB.SetMaxIdleConns(minConns)
+
+	// Test connection
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		tunnel.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", db.WrapTLSError(err))
+	}
+
+	conn := newConnection(sqlDB)
+	conn.tunnel = tunnel
+	return conn, nil
+}
+
+// newConnection wraps an open database handle
+func newConnection(sqlDB *sql.DB) *Connection {
+	return &Connection{
+		db:      sqlDB,
+		timeout: db.DefaultQueryTimeout,
+	}
+}
@@ -0,0 +1,7 @@
+package mysql
+
+import "github.com/imran-vz/gosqlit/internal/db"
+
+func init() {
+	db.RegisterDriver("mysql", &Driver{})
+}
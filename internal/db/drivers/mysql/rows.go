@@ -0,0 +1,92 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// rowIterator implements db.RowIterator over database/sql rows
+type rowIterator struct {
+	rows    *sql.Rows
+	columns []db.Column
+	release func() // stops the kill watcher and returns a pinned connection
+}
+
+// newRowIterator wraps rows, reading their column metadata up front. The
+// driver doesn't expose the source table of a column.
+func newRowIterator(rows *sql.Rows, release func()) (*rowIterator, error) {
+	it := &rowIterator{rows: rows, release: release}
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		it.Close()
+		return nil, err
+	}
+
+	it.columns = make([]db.Column, len(columnTypes))
+	for i, ct := range columnTypes {
+		nullable, ok := ct.Nullable()
+		it.columns[i] = db.Column{
+			Name:     ct.Name(),
+			TypeName: ct.DatabaseTypeName(),
+			Kind:     db.KindForTypeName(ct.DatabaseTypeName()),
+			NotNull:  ok && !nullable,
+		}
+	}
+
+	return it, nil
+}
+
+// Columns returns the result columns
+func (it *rowIterator) Columns() []db.Column {
+	return it.columns
+}
+
+// Next advances to the next row
+func (it *rowIterator) Next() bool {
+	return it.rows.Next()
+}
+
+// Values returns the values of the current row
+func (it *rowIterator) Values() ([]interface{}, error) {
+	values := make([]interface{}, len(it.columns))
+	ptrs := make([]interface{}, len(it.columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := it.rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+
+	// The text protocol hands back every value as bytes
+	for i, v := range values {
+		if b, ok := v.([]byte); ok && !isBinaryType(it.columns[i].TypeName) {
+			values[i] = string(b)
+		}
+	}
+	return values, nil
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *rowIterator) Err() error {
+	return it.rows.Err()
+}
+
+// Close releases the rows and their connection
+func (it *rowIterator) Close() error {
+	err := it.rows.Close()
+	it.release()
+	return err
+}
+
+// skipRows reads and drops the first n rows of it
+func skipRows(it db.RowIterator, n int) error {
+	for i := 0; i < n && it.Next(); i++ {
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("rows error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// Session implements db.Session on a pinned connection
+type Session struct {
+	c        *Connection
+	conn     *sql.Conn
+	threadID uint64
+	status   db.TxStatus
+	page     *openResult // result of the latest paged query, if rows are left
+}
+
+// openResult is a query result kept open between pages, the way the
+// postgres driver keeps a cursor. MySQL has no cursors for plain queries,
+// so the rows stay on the wire and the connection is busy until they're
+// read or closed.
+type openResult struct {
+	sql     string
+	it      *rowIterator
+	pos     int  // rows returned so far
+	pending bool // the iterator is on a row not yet returned
+}
+
+// OpenSession pins a pool connection for a session
+func (c *Connection) OpenSession(ctx context.Context) (db.Session, error) {
+	conn, threadID, err := c.reserveConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		c:        c,
+		conn:     conn,
+		threadID: threadID,
+	}, nil
+}
+
+// Query returns up to limit rows of query starting at offset, or every row
+// when limit <= 0. Asking for the next page of the same query continues
+// where the previous call stopped instead of running it again.
+func (s *Session) Query(ctx context.Context, query string, limit int, offset int) (db.QueryResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.c.timeout)
+	defer cancel()
+
+	if limit <= 0 {
+		it, err := s.QueryRows(ctx, query)
+		if err != nil {
+			return db.QueryResult{}, err
+		}
+		defer it.Close()
+		return db.CollectRows(it, 0)
+	}
+
+	// The watcher only covers this call; the rows outlive it
+	stopWatch := s.c.watchCancel(ctx, s.threadID)
+	defer stopWatch()
+
+	// Next page of the open result
+	if p := s.page; p != nil && offset > 0 && p.sql == query && p.pos == offset {
+		return s.fetchPage(limit)
+	}
+
+	s.closePage()
+
+	// The driver ties the rows to the context they were queried with, and
+	// closes the connection when it's cancelled
+	rows, err := s.conn.QueryContext(context.Background(), query)
+	if err != nil {
+		return db.QueryResult{}, fmt.Errorf("query failed: %w", err)
+	}
+	s.status = trackTxStatus(s.status, query)
+
+	it, err := newRowIterator(rows, func() {})
+	if err != nil {
+		return db.QueryResult{}, fmt.Errorf("failed to read columns: %w", err)
+	}
+	s.page = &openResult{sql: query, it: it}
+
+	if offset > 0 {
+		if err := skipRows(it, offset); err != nil {
+			s.closePage()
+			return db.QueryResult{}, err
+		}
+		s.page.pos = offset
+	}
+
+	return s.fetchPage(limit)
+}
+
+// fetchPage reads up to limit rows from the open result, closing it once
+// it's exhausted or broken
+func (s *Session) fetchPage(limit int) (db.QueryResult, error) {
+	p := s.page
+
+	var rows [][]interface{}
+	for len(rows) < limit {
+		if !p.pending && !p.it.Next() {
+			break
+		}
+		p.pending = false
+
+		values, err := p.it.Values()
+		if err != nil {
+			s.closePage()
+			return db.QueryResult{}, fmt.Errorf("failed to scan row: %w", err)
+		}
+		rows = append(rows, values)
+	}
+	p.pending = len(rows) == limit && p.it.Next()
+
+	if err := p.it.Err(); err != nil {
+		s.closePage()
+		return db.QueryResult{}, fmt.Errorf("rows error: %w", err)
+	}
+	p.pos += len(rows)
+
+	result := db.QueryResult{
+		Columns:  p.it.Columns(),
+		Rows:     rows,
+		RowCount: len(rows),
+		HasMore:  p.pending,
+	}
+	if !p.pending {
+		s.closePage()
+	}
+	return result, nil
+}
+
+// closePage closes the open result, if any. The driver reads and drops
+// the rows left on the wire.
+func (s *Session) closePage() {
+	if s.page != nil {
+		s.page.it.Close()
+		s.page = nil
+	}
+}
+
+// QueryRows executes SQL and streams its rows. The session is busy until
+// the iterator is closed.
+func (s *Session) QueryRows(ctx context.Context, query string) (db.RowIterator, error) {
+	s.closePage()
+	stopWatch := s.c.watchCancel(ctx, s.threadID)
+
+	rows, err := s.conn.QueryContext(ctx, query)
+	if err != nil {
+		stopWatch()
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	s.status = trackTxStatus(s.status, query)
+
+	it, err := newRowIterator(rows, stopWatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	return it, nil
+}
+
+// Exec executes a statement that returns no rows
+func (s *Session) Exec(ctx context.Context, query string) (db.ExecResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.c.timeout)
+	defer cancel()
+
+	s.closePage()
+	stopWatch := s.c.watchCancel(ctx, s.threadID)
+	defer stopWatch()
+
+	res, err := s.conn.ExecContext(ctx, query)
+	if err != nil {
+		return db.ExecResult{}, fmt.Errorf("exec failed: %w", err)
+	}
+	s.status = trackTxStatus(s.status, query)
+
+	affected, _ := res.RowsAffected()
+	return db.ExecResult{
+		CommandTag:   db.CommandTag(query, affected),
+		RowsAffected: affected,
+	}, nil
+}
+
+// TxStatus reports whether a transaction was begun and not yet ended
+func (s *Session) TxStatus() db.TxStatus {
+	return s.status
+}
+
+// Close closes the connection rather than returning it to the pool, so the
+// next pool user doesn't pick up the session's variables, temporary tables
+// or locks. Closing it rolls back any open transaction.
+func (s *Session) Close() error {
+	// Open rows hold the connection, so Raw would wait on them
+	s.closePage()
+
+	// A connection Raw reports as bad is closed, not pooled
+	if err := s.conn.Raw(func(any) error { return driver.ErrBadConn }); !errors.Is(err, driver.ErrBadConn) {
+		return err
+	}
+	return nil
+}
+
+// trackTxStatus follows explicit transaction statements and the implicit
+// commit MySQL performs before DDL and similar statements
+func trackTxStatus(status db.TxStatus, query string) db.TxStatus {
+	switch db.FirstKeyword(query) {
+	case "CREATE", "ALTER", "DROP", "RENAME", "TRUNCATE", "LOCK", "UNLOCK", "GRANT", "REVOKE":
+		return db.TxIdle
+	default:
+		return db.TrackTxStatus(status, query)
+	}
+}
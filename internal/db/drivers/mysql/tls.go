@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// applyTLS sets up cfg for the TLS settings, following libpq's sslmode
+// semantics: allow and prefer fall back to plaintext, require encrypts
+// without verifying, verify-ca checks the chain and verify-full the host too
+func applyTLS(cfg *mysql.Config, settings db.TLSConfig, host string) error {
+	if settings.Mode == "" || settings.Mode == "disable" {
+		return nil
+	}
+
+	tlsConfig := &tls.Config{ServerName: host}
+	if settings.ServerName != "" {
+		tlsConfig.ServerName = settings.ServerName
+	}
+
+	if settings.CAFile != "" {
+		pem, err := os.ReadFile(settings.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA file %s", settings.CAFile)
+		}
+	}
+
+	if settings.CertFile != "" || settings.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(settings.CertFile, settings.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	switch settings.Mode {
+	case "allow", "prefer":
+		tlsConfig.InsecureSkipVerify = true
+		cfg.AllowFallbackToPlaintext = true
+	case "require":
+		tlsConfig.InsecureSkipVerify = true
+	case "verify-ca":
+		// Check the chain but not the name, which Go can't do by itself
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return fmt.Errorf("server presented no certificate")
+			}
+			opts := x509.VerifyOptions{
+				Roots:         tlsConfig.RootCAs,
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, cert := range state.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			_, err := state.PeerCertificates[0].Verify(opts)
+			return err
+		}
+	case "verify-full":
+	default:
+		return fmt.Errorf("unknown SSL mode: %q", settings.Mode)
+	}
+
+	cfg.TLS = tlsConfig
+	return nil
+}
@@ -0,0 +1,21 @@
+package mysql
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+func TestVerifyCANoCertificate(t *testing.T) {
+	cfg := mysql.NewConfig()
+	if err := applyTLS(cfg, db.TLSConfig{Mode: "verify-ca"}, "localhost"); err != nil {
+		t.Fatalf("applyTLS() error = %v", err)
+	}
+
+	err := cfg.TLS.VerifyConnection(tls.ConnectionState{})
+	if err == nil || err.Error() != "server presented no certificate" {
+		t.Errorf("VerifyConnection() error = %v, want server presented no certificate", err)
+	}
+}
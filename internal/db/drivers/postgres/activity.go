@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// ListBackends returns the server's backends from pg_stat_activity, busy
+// ones first, leaving out the one running this query
+func (c *Connection) ListBackends(ctx context.Context) ([]db.Backend, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rows, err := c.pool.Query(ctx, `
+		SELECT pid, coalesce(datname, ''), coalesce(usename, ''), coalesce(application_name, ''),
+			coalesce(client_addr::text, ''), coalesce(state, ''),
+			coalesce(wait_event_type || ': ' || wait_event, ''), query_start, coalesce(query, '')
+		FROM pg_stat_activity
+		WHERE pid <> pg_backend_pid()
+		ORDER BY state = 'active' DESC NULLS LAST, query_start NULLS LAST, pid
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var backends []db.Backend
+	for rows.Next() {
+		var b db.Backend
+		var queryStart *time.Time
+		if err := rows.Scan(&b.PID, &b.Database, &b.User, &b.Application, &b.ClientAddr,
+			&b.State, &b.WaitEvent, &queryStart, &b.Query); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		if queryStart != nil {
+			b.QueryStart = *queryStart
+		}
+		backends = append(backends, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return backends, nil
+}
+
+// CancelBackend cancels the query running on backend pid
+func (c *Connection) CancelBackend(ctx context.Context, pid int) error {
+	return c.signalBackend(ctx, "pg_cancel_backend", pid)
+}
+
+// TerminateBackend ends backend pid, closing its client's connection
+func (c *Connection) TerminateBackend(ctx context.Context, pid int) error {
+	return c.signalBackend(ctx, "pg_terminate_backend", pid)
+}
+
+// signalBackend calls pg_cancel_backend or pg_terminate_backend, which
+// return false when there is no such backend
+func (c *Connection) signalBackend(ctx context.Context, function string, pid int) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var ok bool
+	if err := c.pool.QueryRow(ctx, "SELECT "+function+"($1)", pid).Scan(&ok); err != nil {
+		return fmt.Errorf("%s failed: %w", function, err)
+	}
+	if !ok {
+		return fmt.Errorf("%s failed: no backend with pid %d", function, pid)
+	}
+	return nil
+}
+
+// ListLockWaits returns the backends that wait for a lock, per
+// pg_blocking_pids, and those they wait for, with the lock each is waiting
+// for. waitstart needs PostgreSQL 14.
+func (c *Connection) ListLockWaits(ctx context.Context) ([]db.LockWait, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// pg_locks.waitstart is new in PostgreSQL 14
+	version, err := c.serverVersionNum(ctx)
+	if err != nil {
+		return nil, err
+	}
+	waitStart := "waitstart"
+	if version < 140000 {
+		waitStart = "NULL::timestamptz AS waitstart"
+	}
+
+	rows, err := c.pool.Query(ctx, `
+		WITH blocked AS (
+			SELECT pid, pg_blocking_pids(pid) AS blocked_by
+			FROM pg_stat_activity
+			WHERE cardinality(pg_blocking_pids(pid)) > 0
+		), involved AS (
+			SELECT pid FROM blocked
+			UNION
+			SELECT unnest(blocked_by) FROM blocked
+		)
+		SELECT a.pid, coalesce(b.blocked_by, '{}'), coalesce(a.datname, ''), coalesce(a.usename, ''),
+			coalesce(a.application_name, ''), coalesce(a.client_addr::text, ''), coalesce(a.state, ''),
+			coalesce(a.wait_event_type || ': ' || a.wait_event, ''), a.query_start, coalesce(a.query, ''),
+			coalesce(l.locktype, ''), coalesce(l.mode, ''), coalesce(l.relation::regclass::text, ''), l.waitstart
+		FROM involved i
+		JOIN pg_stat_activity a ON a.pid = i.pid
+		LEFT JOIN blocked b ON b.pid = i.pid
+		LEFT JOIN LATERAL (
+			SELECT locktype, mode, relation, `+waitStart+`
+			FROM pg_locks
+			WHERE pid = i.pid AND NOT granted
+			LIMIT 1
+		) l ON true
+		ORDER BY a.pid
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lock waits: %w", err)
+	}
+	defer rows.Close()
+
+	var waits []db.LockWait
+	for rows.Next() {
+		var w db.LockWait
+		var blockedBy []int32
+		var queryStart, waitStart *time.Time
+		if err := rows.Scan(&w.PID, &blockedBy, &w.Database, &w.User, &w.Application, &w.ClientAddr,
+			&w.State, &w.WaitEvent, &queryStart, &w.Query,
+			&w.LockType, &w.Mode, &w.Relation, &waitStart); err != nil {
+			return nil, fmt.Errorf("failed to scan lock wait: %w", err)
+		}
+		for _, pid := range blockedBy {
+			w.BlockedBy = append(w.BlockedBy, int(pid))
+		}
+		if queryStart != nil {
+			w.QueryStart = *queryStart
+		}
+		if waitStart != nil {
+			w.WaitStart = *waitStart
+		}
+		waits = append(waits, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list lock waits: %w", err)
+	}
+
+	return waits, nil
+}
+
+// serverVersionNum returns the server's version as a number, e.g. 140005
+func (c *Connection) serverVersionNum(ctx context.Context) (int, error) {
+	var version int
+	if err := c.pool.QueryRow(ctx, "SELECT current_setting('server_version_num')::int").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to get server version: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/imran-vz/gosqlit/internal/debug"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// catalog resolves result column metadata that the wire protocol only gives
+// as OIDs: names of types pgx doesn't know and the table columns results
+// come from. Lookups are cached for the life of the connection.
+type catalog struct {
+	pool *pgxpool.Pool
+
+	mu     sync.Mutex
+	types  map[uint32]db.Column            // TypeName and Kind only
+	tables map[uint32]map[uint16]db.Column // by table OID and attribute number
+}
+
+// newCatalog creates an empty catalog cache
+func newCatalog(pool *pgxpool.Pool) *catalog {
+	return &catalog{
+		pool:   pool,
+		types:  make(map[uint32]db.Column),
+		tables: make(map[uint32]map[uint16]db.Column),
+	}
+}
+
+// columnsFromFields describes result columns with what the field
+// descriptions alone tell
+func columnsFromFields(fields []pgconn.FieldDescription, typeMap *pgtype.Map) []db.Column {
+	columns := make([]db.Column, len(fields))
+	for i, field := range fields {
+		columns[i].Name = field.Name
+		if t, ok := typeMap.TypeForOID(field.DataTypeOID); ok {
+			columns[i].TypeName = t.Name
+			columns[i].Kind = db.KindForTypeName(t.Name)
+		}
+	}
+	return columns
+}
+
+// describe fills in type names pgx doesn't know, and the source table,
+// column and nullability of columns read straight from a table. Lookups
+// run on the pool so they can't disturb a session's transaction; failures
+// only leave the metadata incomplete.
+func (cat *catalog) describe(ctx context.Context, columns []db.Column, fields []pgconn.FieldDescription) {
+	cat.mu.Lock()
+	defer cat.mu.Unlock()
+
+	var typeOIDs, tableOIDs []uint32
+	for i, field := range fields {
+		if columns[i].TypeName == "" {
+			if _, ok := cat.types[field.DataTypeOID]; !ok {
+				typeOIDs = append(typeOIDs, field.DataTypeOID)
+			}
+		}
+		if field.TableOID != 0 {
+			if _, ok := cat.tables[field.TableOID]; !ok {
+				tableOIDs = append(tableOIDs, field.TableOID)
+			}
+		}
+	}
+
+	if len(typeOIDs) > 0 {
+		if err := cat.loadTypes(ctx, typeOIDs); err != nil {
+			debug.LogError(err, "postgres/load_types")
+		}
+	}
+	if len(tableOIDs) > 0 {
+		if err := cat.loadTables(ctx, tableOIDs); err != nil {
+			debug.LogError(err, "postgres/load_tables")
+		}
+	}
+
+	for i, field := range fields {
+		if t, ok := cat.types[field.DataTypeOID]; ok && columns[i].TypeName == "" {
+			columns[i].TypeName = t.TypeName
+			columns[i].Kind = t.Kind
+		}
+		if source, ok := cat.tables[field.TableOID][field.TableAttributeNumber]; ok {
+			columns[i].NotNull = source.NotNull
+			columns[i].Schema = source.Schema
+			columns[i].Table = source.Table
+			columns[i].TableColumn = source.TableColumn
+		}
+	}
+}
+
+// loadTypes caches names and kinds of types by OID
+func (cat *catalog) loadTypes(ctx context.Context, oids []uint32) error {
+	rows, err := cat.pool.Query(ctx, `
+		SELECT oid, typname, typcategory
+		FROM pg_type
+		WHERE oid = ANY($1)
+	`, oids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var oid uint32
+		var name, category string
+		if err := rows.Scan(&oid, &name, &category); err != nil {
+			return err
+		}
+
+		// Enums, domains and extension types keep a meaningful category
+		var kind db.ColumnKind
+		switch category {
+		case "S", "E":
+			kind = db.KindText
+		case "N":
+			kind = db.KindNumber
+		case "D":
+			kind = db.KindTime
+		case "A":
+			kind = db.KindArray
+		default:
+			kind = db.KindForTypeName(name)
+		}
+		cat.types[oid] = db.Column{TypeName: name, Kind: kind}
+	}
+
+	return rows.Err()
+}
+
+// loadTables caches the columns of tables by OID
+func (cat *catalog) loadTables(ctx context.Context, oids []uint32) error {
+	rows, err := cat.pool.Query(ctx, `
+		SELECT c.oid, a.attnum, n.nspname, c.relname, a.attname, a.attnotnull
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.oid = ANY($1) AND a.attnum > 0 AND NOT a.attisdropped
+	`, oids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for _, oid := range oids {
+		cat.tables[oid] = make(map[uint16]db.Column)
+	}
+
+	for rows.Next() {
+		var oid uint32
+		var attnum int16
+		var col db.Column
+		if err := rows.Scan(&oid, &attnum, &col.Schema, &col.Table, &col.TableColumn, &col.NotNull); err != nil {
+			return err
+		}
+		cat.tables[oid][uint16(attnum)] = col
+	}
+
+	return rows.Err()
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/imran-vz/gosqlit/internal/db"
@@ -13,57 +14,63 @@ import (
 type Connection struct {
 	pool    *pgxpool.Pool
 	timeout time.Duration
+	run     *runner
+	tunnel  *db.Tunnel // SSH tunnel connections are dialed through, if any
+	notices *noticeLog
 }
 
-// Query executes SQL query with pagination
+// newConnection wraps a connected pool whose OnNotice feeds notices
+func newConnection(pool *pgxpool.Pool, notices *noticeLog) *Connection {
+	return &Connection{
+		pool:    pool,
+		timeout: db.DefaultQueryTimeout,
+		run:     &runner{pool: pool, catalog: newCatalog(pool)},
+		notices: notices,
+	}
+}
+
+// Query executes SQL query, returning up to limit rows starting at offset.
+// Row-returning statements run through a server-side cursor that stays open,
+// so asking for the next page of the same SQL continues where the previous
+// call stopped instead of re-running the query.
 func (c *Connection) Query(ctx context.Context, sql string, limit int, offset int) (db.QueryResult, error) {
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
-	// TODO: Implement pagination with proper query breakup
-	// Add LIMIT and OFFSET to query
-	// paginatedSQL := fmt.Sprintf("%s", sql, limit, offset)
+	return c.run.query(ctx, sql, limit, offset)
+}
 
-	rows, err := c.pool.Query(ctx, sql)
-	if err != nil {
-		return db.QueryResult{}, fmt.Errorf("query failed: %w", err)
-	}
-	defer rows.Close()
+// QueryRows executes SQL and streams its rows. The connection timeout is not
+// applied since reading a large result can take arbitrarily long; cancel ctx
+// to stop.
+func (c *Connection) QueryRows(ctx context.Context, sql string) (db.RowIterator, error) {
+	return c.run.queryRows(ctx, sql)
+}
 
-	// Get column names
-	fieldDescs := rows.FieldDescriptions()
-	columns := make([]string, len(fieldDescs))
-	for i, field := range fieldDescs {
-		columns[i] = string(field.Name)
-	}
+// Exec executes a statement that returns no rows
+func (c *Connection) Exec(ctx context.Context, sql string) (db.ExecResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
 
-	// Collect rows
-	var resultRows [][]interface{}
-	for rows.Next() {
-		values, err := rows.Values()
-		if err != nil {
-			return db.QueryResult{}, fmt.Errorf("failed to scan row: %w", err)
-		}
-		resultRows = append(resultRows, values)
-	}
+	return c.run.exec(ctx, sql)
+}
 
-	if err := rows.Err(); err != nil {
-		return db.QueryResult{}, fmt.Errorf("rows error: %w", err)
+// OpenSession pins a pool connection for a session
+func (c *Connection) OpenSession(ctx context.Context) (db.Session, error) {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
 	}
 
-	// Check if there are more rows
-	hasMore := len(resultRows) == limit
-
-	return db.QueryResult{
-		Columns:    columns,
-		Rows:       resultRows,
-		RowCount:   len(resultRows),
-		HasMore:    hasMore,
-		ResultSets: []db.QueryResultSet{}, // Single result set for now
+	c.notices.watch(conn.Conn().PgConn())
+	return &Session{
+		timeout: c.timeout,
+		run:     &runner{pool: c.pool, conn: conn, catalog: c.run.catalog},
+		notices: c.notices,
 	}, nil
 }
 
-// ListSchemas returns all schemas
+// ListSchemas returns all schemas, without their tables
 func (c *Connection) ListSchemas(ctx context.Context) ([]db.Schema, error) {
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
@@ -87,32 +94,45 @@ func (c *Connection) ListSchemas(ctx context.Context) ([]db.Schema, error) {
 		if err := rows.Scan(&schemaName); err != nil {
 			return nil, fmt.Errorf("failed to scan schema: %w", err)
 		}
-
-		// Get tables for this schema
-		tables, err := c.ListTables(ctx, schemaName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list tables for schema %s: %w", schemaName, err)
-		}
-
-		schemas = append(schemas, db.Schema{
-			Name:   schemaName,
-			Tables: tables,
-		})
+		schemas = append(schemas, db.Schema{Name: schemaName})
 	}
 
-	return schemas, nil
+	return schemas, rows.Err()
 }
 
-// ListTables returns tables in a schema
+// ListTables returns the tables, views, functions, sequences and types in
+// a schema. Objects that belong to an extension are left out.
 func (c *Connection) ListTables(ctx context.Context, schema string) ([]db.Table, error) {
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
+	// Kinds are relkind, 'fn:' prokind or 'type:' typtype
 	query := `
-		SELECT table_name
-		FROM information_schema.tables
-		WHERE table_schema = $1 AND table_type = 'BASE TABLE'
-		ORDER BY table_name
+		WITH ns AS (SELECT oid FROM pg_namespace WHERE nspname = $1)
+		SELECT c.relname, c.relkind::text, ''
+		FROM pg_class c
+		WHERE c.relnamespace = (SELECT oid FROM ns)
+		  AND c.relkind IN ('r', 'p', 'v', 'm', 'f', 'S')
+		  AND NOT EXISTS (
+			SELECT 1 FROM pg_depend d
+			WHERE d.classid = 'pg_class'::regclass AND d.objid = c.oid AND d.deptype = 'e')
+		UNION ALL
+		SELECT p.proname, 'fn:' || p.prokind, pg_get_function_identity_arguments(p.oid)
+		FROM pg_proc p
+		WHERE p.pronamespace = (SELECT oid FROM ns)
+		  AND NOT EXISTS (
+			SELECT 1 FROM pg_depend d
+			WHERE d.classid = 'pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e')
+		UNION ALL
+		SELECT t.typname, 'type:' || t.typtype, ''
+		FROM pg_type t
+		LEFT JOIN pg_class c ON c.oid = t.typrelid
+		WHERE t.typnamespace = (SELECT oid FROM ns)
+		  AND (t.typtype IN ('e', 'd', 'r') OR (t.typtype = 'c' AND c.relkind = 'c'))
+		  AND NOT EXISTS (
+			SELECT 1 FROM pg_depend d
+			WHERE d.classid = 'pg_type'::regclass AND d.objid = t.oid AND d.deptype = 'e')
+		ORDER BY 1, 3
 	`
 
 	rows, err := c.pool.Query(ctx, query, schema)
@@ -123,72 +143,48 @@ func (c *Connection) ListTables(ctx context.Context, schema string) ([]db.Table,
 
 	var tables []db.Table
 	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
+		var name, kind, args string
+		if err := rows.Scan(&name, &kind, &args); err != nil {
 			return nil, fmt.Errorf("failed to scan table: %w", err)
 		}
 
-		tables = append(tables, db.Table{
-			Name:   tableName,
+		table := db.Table{
+			Name:   name,
 			Schema: schema,
-		})
+			Kind:   objectKind(kind),
+		}
+		if table.Kind == db.ObjectFunction || table.Kind == db.ObjectProcedure {
+			table.Signature = "(" + args + ")"
+		}
+		tables = append(tables, table)
 	}
 
-	return tables, nil
+	return tables, rows.Err()
 }
 
-// GetTableInfo returns table metadata
-func (c *Connection) GetTableInfo(ctx context.Context, schema, table string) (db.TableInfo, error) {
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	query := `
-		SELECT
-			column_name,
-			data_type,
-			is_nullable,
-			COALESCE(
-				(SELECT constraint_type
-				 FROM information_schema.table_constraints tc
-				 JOIN information_schema.key_column_usage kcu
-				   ON tc.constraint_name = kcu.constraint_name
-				  AND tc.table_schema = kcu.table_schema
-				 WHERE tc.table_schema = $1
-				   AND tc.table_name = $2
-				   AND kcu.column_name = c.column_name
-				 LIMIT 1),
-				''
-			) as key_type
-		FROM information_schema.columns c
-		WHERE table_schema = $1 AND table_name = $2
-		ORDER BY ordinal_position
-	`
-
-	rows, err := c.pool.Query(ctx, query, schema, table)
-	if err != nil {
-		return db.TableInfo{}, fmt.Errorf("failed to get table info: %w", err)
+// objectKind maps a kind from ListTables' query to the db kind
+func objectKind(kind string) db.ObjectKind {
+	switch kind {
+	case "v":
+		return db.ObjectView
+	case "m":
+		return db.ObjectMaterializedView
+	case "f":
+		return db.ObjectForeignTable
+	case "S":
+		return db.ObjectSequence
+	case "fn:p":
+		return db.ObjectProcedure
+	case "type:e":
+		return db.ObjectEnum
 	}
-	defer rows.Close()
-
-	var columns []db.ColumnInfo
-	for rows.Next() {
-		var col db.ColumnInfo
-		var nullable string
-		var keyType string
-
-		if err := rows.Scan(&col.Name, &col.Type, &nullable, &keyType); err != nil {
-			return db.TableInfo{}, fmt.Errorf("failed to scan column: %w", err)
-		}
-
-		col.Nullable = (nullable == "YES")
-		col.Key = convertKeyType(keyType)
-
-		columns = append(columns, col)
+	switch {
+	case strings.HasPrefix(kind, "fn:"):
+		return db.ObjectFunction
+	case strings.HasPrefix(kind, "type:"):
+		return db.ObjectType
 	}
-
-	return db.TableInfo{
-		Columns: columns,
-	}, nil
+	return db.ObjectTable
 }
 
 // Ping checks if connection is alive
@@ -199,9 +195,12 @@ func (c *Connection) Ping(ctx context.Context) error {
 	return c.pool.Ping(ctx)
 }
 
-// Close closes the connection
+// Close closes the connection. Open sessions must be closed first, as the
+// pool waits for their connections.
 func (c *Connection) Close() error {
+	c.run.close()
 	c.pool.Close()
+	c.tunnel.Close()
 	return nil
 }
 
@@ -209,17 +208,3 @@ func (c *Connection) Close() error {
 func (c *Connection) SetTimeout(duration time.Duration) {
 	c.timeout = duration
 }
-
-// convertKeyType converts PostgreSQL constraint type to simplified key indicator
-func convertKeyType(constraintType string) string {
-	switch constraintType {
-	case "PRIMARY KEY":
-		return "PRI"
-	case "UNIQUE":
-		return "UNI"
-	case "FOREIGN KEY":
-		return "MUL"
-	default:
-		return ""
-	}
-}
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const cursorName = "gosqlit_cursor"
+
+// cursor is a server-side cursor kept open between page fetches. Outside a
+// transaction it begins its own, which lasts until the result is exhausted or
+// another statement replaces it. Inside a session's transaction it lives in
+// that transaction instead.
+type cursor struct {
+	sql       string
+	conn      *pgx.Conn
+	release   func() // returns a pool connection, nil when a session holds it
+	ownTx     bool   // the cursor began its own transaction
+	columns   []db.Column
+	pos       int           // rows handed out so far
+	lookahead []interface{} // first row of the next page, nil if none
+}
+
+// errCursorUnsupported means the statement can't run as a cursor and should
+// be executed directly instead
+var errCursorUnsupported = errors.New("statement not supported by cursor")
+
+// openCursor declares a cursor for sql on conn
+func openCursor(ctx context.Context, conn *pgx.Conn, sql string) (*cursor, error) {
+	switch db.FirstKeyword(sql) {
+	case "SELECT", "WITH", "VALUES", "TABLE":
+	default:
+		return nil, errCursorUnsupported
+	}
+
+	// Cursors only exist inside a transaction. Within the user's own, a
+	// savepoint keeps a rejected DECLARE from aborting it.
+	var begin, undo, done string
+	switch conn.PgConn().TxStatus() {
+	case 'I':
+		begin, undo = "BEGIN", "ROLLBACK"
+	case 'T':
+		begin = "SAVEPOINT " + cursorName
+		undo = "ROLLBACK TO SAVEPOINT " + cursorName + "; RELEASE SAVEPOINT " + cursorName
+		done = "RELEASE SAVEPOINT " + cursorName
+	default:
+		// A failed transaction rejects everything; let the statement say so
+		return nil, errCursorUnsupported
+	}
+	ownTx := begin == "BEGIN"
+
+	if _, err := conn.Exec(ctx, begin); err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Extended protocol so a script with several statements can't sneak
+	// past the DECLARE
+	declare := fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", cursorName, db.TrimStatement(sql))
+	if _, err := conn.PgConn().ExecParams(ctx, declare, nil, nil, nil, nil).Close(); err != nil {
+		conn.Exec(context.Background(), undo)
+
+		// Server-side rejections (data-modifying WITH, SELECT INTO, syntax
+		// errors) are reported more clearly by running the statement as-is
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			return nil, errCursorUnsupported
+		}
+		return nil, fmt.Errorf("failed to declare cursor: %w", err)
+	}
+
+	if done != "" {
+		if _, err := conn.Exec(ctx, done); err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+	}
+
+	return &cursor{
+		sql:   sql,
+		conn:  conn,
+		ownTx: ownTx,
+	}, nil
+}
+
+// skip moves the cursor forward without transferring rows
+func (cur *cursor) skip(ctx context.Context, count int) error {
+	if _, err := cur.conn.Exec(ctx, fmt.Sprintf("MOVE FORWARD %d IN %s", count, cursorName)); err != nil {
+		return fmt.Errorf("failed to move cursor: %w", err)
+	}
+	cur.pos += count
+	return nil
+}
+
+// fetch returns the next page of up to limit rows. Column metadata is
+// resolved through cat on the first page.
+func (cur *cursor) fetch(ctx context.Context, limit int, cat *catalog) (db.QueryResult, error) {
+	var resultRows [][]interface{}
+	if cur.lookahead != nil {
+		resultRows = append(resultRows, cur.lookahead)
+		cur.lookahead = nil
+	}
+
+	// Fetch one row past the page so HasMore is exact
+	count := limit + 1 - len(resultRows)
+	rows, err := cur.conn.Query(ctx,
+		fmt.Sprintf("FETCH FORWARD %d FROM %s", count, cursorName),
+		pgx.QueryExecModeSimpleProtocol,
+	)
+	if err != nil {
+		return db.QueryResult{}, fmt.Errorf("query failed: %w", err)
+	}
+	it := &rowIterator{rows: rows}
+	defer it.Close()
+
+	page, err := db.CollectRows(it, 0)
+	if err != nil {
+		return db.QueryResult{}, err
+	}
+	if cur.columns == nil {
+		cat.describe(ctx, page.Columns, it.fields())
+		cur.columns = page.Columns
+	}
+	resultRows = append(resultRows, page.Rows...)
+
+	hasMore := len(resultRows) > limit
+	if hasMore {
+		cur.lookahead = resultRows[limit]
+		resultRows = resultRows[:limit]
+	}
+	cur.pos += len(resultRows)
+
+	return db.QueryResult{
+		Columns:    cur.columns,
+		Rows:       resultRows,
+		RowCount:   len(resultRows),
+		HasMore:    hasMore,
+		ResultSets: []db.QueryResultSet{}, // Single result set for now
+	}, nil
+}
+
+// close drops the cursor and returns its connection to the pool. A cursor
+// transaction is committed to keep side effects of functions called by the
+// query, matching what a plain query would have done.
+func (cur *cursor) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if cur.ownTx {
+		if _, err := cur.conn.Exec(ctx, "COMMIT"); err != nil {
+			cur.conn.Exec(ctx, "ROLLBACK")
+		}
+	} else {
+		// Fails harmlessly if the transaction has been aborted meanwhile
+		cur.conn.Exec(ctx, "CLOSE "+cursorName)
+	}
+
+	if cur.release != nil {
+		cur.release()
+	}
+}
@@ -0,0 +1,347 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/jackc/pgx/v5"
+)
+
+// ObjectDDL returns the statements that create object. Views and functions
+// are printed by the server; tables and sequences are assembled from the
+// catalogs, with their indexes and comments.
+func (c *Connection) ObjectDDL(ctx context.Context, object db.Table) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var ddl string
+	var err error
+	switch object.Kind {
+	case db.ObjectTable:
+		ddl, err = c.tableDDL(ctx, object)
+	case db.ObjectView, db.ObjectMaterializedView:
+		ddl, err = c.viewDDL(ctx, object)
+	case db.ObjectFunction, db.ObjectProcedure:
+		ddl, err = c.functionDDL(ctx, object)
+	case db.ObjectSequence:
+		ddl, err = c.sequenceDDL(ctx, object)
+	case db.ObjectEnum:
+		ddl, err = c.enumDDL(ctx, object)
+	default:
+		return "", fmt.Errorf("DDL is not supported for %s", object.Name)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("%s.%s not found", object.Schema, object.Name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get DDL: %w", err)
+	}
+	return ddl, nil
+}
+
+// partitioning is how a table takes part in declarative partitioning
+type partitioning struct {
+	key    string // PARTITION BY clause of a partitioned table
+	parent string // qualified parent of a partition
+	bound  string // FOR VALUES clause of a partition
+
+	// Constraints and indexes of a partition that aren't inherited from
+	// its parent
+	localConstraints map[string]bool
+	localIndexes     map[string]bool
+}
+
+// tablePartitioning looks up whether a table is partitioned or a partition.
+// Servers before PostgreSQL 10 have neither.
+func (c *Connection) tablePartitioning(ctx context.Context, object db.Table) (partitioning, error) {
+	var part partitioning
+
+	version, err := c.serverVersionNum(ctx)
+	if err != nil || version < 100000 {
+		return part, err
+	}
+
+	// Constraints cloned from a partitioned table's, PG11+, have a parent
+	localConstraint := "con.conislocal"
+	if version >= 110000 {
+		localConstraint += " AND con.conparentid = 0"
+	}
+
+	var isPartition bool
+	var constraintNames, indexNames []string
+	err = c.pool.QueryRow(ctx, `
+		SELECT coalesce(CASE WHEN c.relkind = 'p' THEN 'PARTITION BY ' || pg_get_partkeydef(c.oid) END, ''),
+			c.relispartition,
+			coalesce((SELECT quote_ident(pn.nspname) || '.' || quote_ident(p.relname)
+				FROM pg_inherits h
+				JOIN pg_class p ON p.oid = h.inhparent
+				JOIN pg_namespace pn ON pn.oid = p.relnamespace
+				WHERE h.inhrelid = c.oid), ''),
+			coalesce(pg_get_expr(c.relpartbound, c.oid), ''),
+			ARRAY(SELECT con.conname::text FROM pg_constraint con WHERE con.conrelid = c.oid AND `+localConstraint+`),
+			ARRAY(SELECT i.relname::text
+				FROM pg_index x
+				JOIN pg_class i ON i.oid = x.indexrelid
+				WHERE x.indrelid = c.oid
+					AND NOT EXISTS (SELECT 1 FROM pg_inherits h WHERE h.inhrelid = x.indexrelid))
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2
+	`, object.Schema, object.Name).Scan(&part.key, &isPartition, &part.parent, &part.bound, &constraintNames, &indexNames)
+	if err != nil || !isPartition {
+		return partitioning{key: part.key}, err
+	}
+
+	part.localConstraints = make(map[string]bool)
+	for _, name := range constraintNames {
+		part.localConstraints[name] = true
+	}
+	part.localIndexes = make(map[string]bool)
+	for _, name := range indexNames {
+		part.localIndexes[name] = true
+	}
+	return part, nil
+}
+
+// tableDDL assembles CREATE TABLE with the table's constraints, then its
+// other indexes and comments. A partitioned table gets its PARTITION BY,
+// and a partition is created PARTITION OF its parent, whose columns,
+// constraints and indexes it inherits.
+func (c *Connection) tableDDL(ctx context.Context, object db.Table) (string, error) {
+	info, err := c.GetTableInfo(ctx, object.Schema, object.Name)
+	if err != nil {
+		return "", err
+	}
+	part, err := c.tablePartitioning(ctx, object)
+	if err != nil {
+		return "", err
+	}
+	isPartition := part.parent != ""
+
+	names := []string{object.Schema, object.Name}
+	for _, col := range info.Columns {
+		names = append(names, col.Name)
+	}
+	if info.PrimaryKey != nil {
+		names = append(names, info.PrimaryKey.Name)
+	}
+	for _, constraints := range [][]db.Constraint{info.Uniques, info.Checks} {
+		for _, constraint := range constraints {
+			names = append(names, constraint.Name)
+		}
+	}
+	for _, fk := range info.ForeignKeys {
+		names = append(names, fk.Name)
+	}
+	quoted, err := c.quoteIdents(ctx, names)
+	if err != nil {
+		return "", err
+	}
+	table := quoted[object.Schema] + "." + quoted[object.Name]
+
+	var lines []string
+	for _, col := range info.Columns {
+		if isPartition {
+			break
+		}
+		line := quoted[col.Name] + " " + col.Type
+		switch {
+		case col.Identity != "":
+			line += " GENERATED " + col.Identity + " AS IDENTITY"
+		case col.Generated != "":
+			line += " GENERATED ALWAYS AS (" + col.Generated + ") STORED"
+		case col.Default != "":
+			line += " DEFAULT " + col.Default
+		}
+		if !col.Nullable {
+			line += " NOT NULL"
+		}
+		lines = append(lines, line)
+	}
+
+	// Constraints are named as in the catalog so the DDL reproduces them
+	constraint := func(name, definition string) string {
+		return "CONSTRAINT " + quoted[name] + " " + definition
+	}
+	// A partition's inherited ones come with PARTITION OF
+	own := func(name string) bool {
+		return !isPartition || part.localConstraints[name]
+	}
+	constraintIndexes := make(map[string]bool)
+	if pk := info.PrimaryKey; pk != nil {
+		if own(pk.Name) {
+			lines = append(lines, constraint(pk.Name, pk.Definition))
+		}
+		constraintIndexes[pk.Name] = true
+	}
+	for _, unique := range info.Uniques {
+		if own(unique.Name) {
+			lines = append(lines, constraint(unique.Name, unique.Definition))
+		}
+		constraintIndexes[unique.Name] = true
+	}
+	for _, check := range info.Checks {
+		if own(check.Name) {
+			lines = append(lines, constraint(check.Name, check.Definition))
+		}
+	}
+	for _, fk := range info.ForeignKeys {
+		if own(fk.Name) {
+			lines = append(lines, constraint(fk.Name, fk.Definition))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("CREATE TABLE " + table)
+	if isPartition {
+		b.WriteString(" PARTITION OF " + part.parent)
+	}
+	if !isPartition || len(lines) > 0 {
+		fmt.Fprintf(&b, " (\n    %s\n)", strings.Join(lines, ",\n    "))
+	}
+	if isPartition {
+		b.WriteString(" " + part.bound)
+	}
+	if part.key != "" {
+		b.WriteString(" " + part.key)
+	}
+	b.WriteString(";\n")
+
+	// Indexes backing constraints are created with them
+	for _, index := range info.Indexes {
+		if !constraintIndexes[index.Name] && (!isPartition || part.localIndexes[index.Name]) {
+			fmt.Fprintf(&b, "\n%s;\n", index.Definition)
+		}
+	}
+
+	if info.Comment != "" {
+		fmt.Fprintf(&b, "\nCOMMENT ON TABLE %s IS %s;\n", table, quoteLiteral(info.Comment))
+	}
+	for _, col := range info.Columns {
+		if col.Comment != "" {
+			fmt.Fprintf(&b, "COMMENT ON COLUMN %s.%s IS %s;\n", table, quoted[col.Name], quoteLiteral(col.Comment))
+		}
+	}
+
+	return b.String(), nil
+}
+
+// viewDDL returns CREATE VIEW, or CREATE MATERIALIZED VIEW with its indexes
+func (c *Connection) viewDDL(ctx context.Context, object db.Table) (string, error) {
+	var oid uint32
+	var name, definition string
+	err := c.pool.QueryRow(ctx, `
+		SELECT c.oid, quote_ident(n.nspname) || '.' || quote_ident(c.relname), pg_get_viewdef(c.oid, true)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2
+	`, object.Schema, object.Name).Scan(&oid, &name, &definition)
+	if err != nil {
+		return "", err
+	}
+	definition = strings.TrimSuffix(strings.TrimSpace(definition), ";")
+
+	if object.Kind == db.ObjectView {
+		return fmt.Sprintf("CREATE OR REPLACE VIEW %s AS\n%s;\n", name, definition), nil
+	}
+
+	indexes, err := c.tableIndexes(ctx, oid)
+	if err != nil {
+		return "", err
+	}
+	ddl := fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS\n%s;\n", name, definition)
+	for _, index := range indexes {
+		ddl += "\n" + index.Definition + ";\n"
+	}
+	return ddl, nil
+}
+
+// functionDDL returns CREATE OR REPLACE FUNCTION or PROCEDURE, picking the
+// overload by its argument types
+func (c *Connection) functionDDL(ctx context.Context, object db.Table) (string, error) {
+	args := strings.TrimSuffix(strings.TrimPrefix(object.Signature, "("), ")")
+
+	var definition string
+	err := c.pool.QueryRow(ctx, `
+		SELECT pg_get_functiondef(p.oid)
+		FROM pg_proc p
+		JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE n.nspname = $1 AND p.proname = $2
+		  AND pg_get_function_identity_arguments(p.oid) = $3
+	`, object.Schema, object.Name, args).Scan(&definition)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(definition, "\n") + ";\n", nil
+}
+
+// sequenceDDL assembles CREATE SEQUENCE with the sequence's settings
+func (c *Connection) sequenceDDL(ctx context.Context, object db.Table) (string, error) {
+	var name, dataType string
+	var start, increment, minValue, maxValue, cache int64
+	var cycle bool
+	err := c.pool.QueryRow(ctx, `
+		SELECT quote_ident(schemaname) || '.' || quote_ident(sequencename), data_type::text,
+			start_value, increment_by, min_value, max_value, cache_size, cycle
+		FROM pg_sequences
+		WHERE schemaname = $1 AND sequencename = $2
+	`, object.Schema, object.Name).Scan(&name, &dataType, &start, &increment, &minValue, &maxValue, &cache, &cycle)
+	if err != nil {
+		return "", err
+	}
+
+	cycleClause := "NO CYCLE"
+	if cycle {
+		cycleClause = "CYCLE"
+	}
+	return fmt.Sprintf("CREATE SEQUENCE %s AS %s\n    START WITH %d\n    INCREMENT BY %d\n    MINVALUE %d\n    MAXVALUE %d\n    CACHE %d\n    %s;\n",
+		name, dataType, start, increment, minValue, maxValue, cache, cycleClause), nil
+}
+
+// enumDDL returns CREATE TYPE ... AS ENUM with the labels in order
+func (c *Connection) enumDDL(ctx context.Context, object db.Table) (string, error) {
+	var name string
+	var labels []string
+	err := c.pool.QueryRow(ctx, `
+		SELECT quote_ident(n.nspname) || '.' || quote_ident(t.typname),
+			ARRAY(SELECT e.enumlabel::text FROM pg_enum e WHERE e.enumtypid = t.oid ORDER BY e.enumsortorder)
+		FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = $1 AND t.typname = $2
+	`, object.Schema, object.Name).Scan(&name, &labels)
+	if err != nil {
+		return "", err
+	}
+
+	quotedLabels := make([]string, len(labels))
+	for i, label := range labels {
+		quotedLabels[i] = quoteLiteral(label)
+	}
+	return fmt.Sprintf("CREATE TYPE %s AS ENUM (\n    %s\n);\n", name, strings.Join(quotedLabels, ",\n    ")), nil
+}
+
+// quoteIdents quotes names as the server does, only where needed, keyed
+// by name
+func (c *Connection) quoteIdents(ctx context.Context, names []string) (map[string]string, error) {
+	var quoted []string
+	err := c.pool.QueryRow(ctx, `
+		SELECT ARRAY(SELECT quote_ident(u.name) FROM unnest($1::text[]) WITH ORDINALITY u(name, i) ORDER BY u.i)
+	`, names).Scan(&quoted)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(names))
+	for i, name := range names {
+		result[name] = quoted[i]
+	}
+	return result, nil
+}
+
+// quoteLiteral quotes a string literal
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
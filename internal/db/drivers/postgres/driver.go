@@ -3,9 +3,14 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -22,44 +27,155 @@ func (d *Driver) DefaultPort() int {
 	return 5432
 }
 
-// RequiredFields returns required connection fields
-func (d *Driver) RequiredFields() []string {
-	return []string{"host", "port", "username", "password", "database"}
+// Fields returns the connection settings PostgreSQL takes
+func (d *Driver) Fields() []db.Field {
+	fields := []db.Field{
+		{Key: db.FieldHost, Label: "Host", Default: "localhost"},
+		{Key: db.FieldPort, Label: "Port", Type: db.FieldNumber, Default: strconv.Itoa(d.DefaultPort())},
+		{Key: db.FieldUsername, Label: "Username"},
+		{Key: db.FieldPassword, Label: "Password", Secret: true, Optional: true},
+		{Key: db.FieldDatabase, Label: "Database", Optional: true},
+		{Key: db.FieldParams, Label: "Parameters", Optional: true},
+	}
+	fields = append(fields, db.TLSFields("prefer")...)
+	fields = append(fields, db.SSHFields()...)
+	return append(fields, db.LimitFields()...)
+}
+
+// Schemes returns the connection URL schemes PostgreSQL accepts
+func (d *Driver) Schemes() []string {
+	return []string{"postgres", "postgresql"}
+}
+
+// Capabilities returns what PostgreSQL supports
+func (d *Driver) Capabilities() db.Capabilities {
+	return db.Capabilities{
+		Transactions: true,
+		Schemas:      true,
+		Explain:      true,
+		Cancel:       true,
+		Activity:     true,
+		Notify:       true,
+	}
 }
 
 // Connect establishes connection to PostgreSQL
 func (d *Driver) Connect(ctx context.Context, config db.ConnConfig) (db.Connection, error) {
-	connStr := fmt.Sprintf(
-		"postgres://%s:%s@%s:%d/%s?sslmode=prefer",
-		config.Username,
-		config.Password,
-		config.Host,
-		config.Port,
-		config.Database,
-	)
-
-	poolConfig, err := pgxpool.ParseConfig(connStr)
+	poolConfig, err := pgxpool.ParseConfig(connString(config))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse connection string: %w", err)
 	}
 
+	// pgx verifies against the host unless told otherwise
+	if name := config.TLS.ServerName; name != "" {
+		if tlsConfig := poolConfig.ConnConfig.TLSConfig; tlsConfig != nil {
+			tlsConfig.ServerName = name
+		}
+		for _, fallback := range poolConfig.ConnConfig.Fallbacks {
+			if fallback.TLSConfig != nil {
+				fallback.TLSConfig.ServerName = name
+			}
+		}
+	}
+
 	// Set pool settings
-	poolConfig.MaxConns = 5
-	poolConfig.MinConns = 1
+	maxConns, minConns, err := config.PoolSize()
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.MaxConns = int32(maxConns)
+	poolConfig.MinConns = int32(minConns)
+
+	poolConfig.ConnConfig.ConnectTimeout = db.DefaultConnectTimeout
+	if config.ConnectTimeout > 0 {
+		poolConfig.ConnConfig.ConnectTimeout = config.ConnectTimeout
+	}
+
+	// Server-side limits are set on every connection at startup
+	if config.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = milliseconds(config.StatementTimeout)
+	}
+	if config.LockTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["lock_timeout"] = milliseconds(config.LockTimeout)
+	}
+
+	// Cancel statements with a cancel request instead of dropping the
+	// connection, so a session keeps its transaction
+	poolConfig.ConnConfig.BuildContextWatcherHandler = func(pgConn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{
+			Conn:          pgConn,
+			DeadlineDelay: 5 * time.Second,
+		}
+	}
+
+	// Notices, like RAISE NOTICE, are kept for the statements of sessions
+	notices := newNoticeLog()
+	poolConfig.ConnConfig.OnNotice = notices.receive
+
+	var tunnel *db.Tunnel
+	if config.SSH.Enabled() {
+		tunnel, err = db.OpenTunnel(ctx, config.SSH)
+		if err != nil {
+			return nil, err
+		}
+		poolConfig.ConnConfig.DialFunc = tunnel.Dial
+		// The host is resolved by the SSH server
+		poolConfig.ConnConfig.LookupFunc = func(ctx context.Context, host string) ([]string, error) {
+			return []string{host}, nil
+		}
+	}
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
+		tunnel.Close()
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
 
 	// Test connection
 	if err := pool.Ping(ctx); err != nil {
 		pool.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		tunnel.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", db.WrapTLSError(err))
+	}
+
+	conn := newConnection(pool, notices)
+	conn.tunnel = tunnel
+	return conn, nil
+}
+
+// milliseconds formats d as a setting value in milliseconds
+func milliseconds(d time.Duration) string {
+	return strconv.FormatInt(d.Milliseconds(), 10)
+}
+
+// connString builds a connection URL from config, escaping every part.
+// TLS settings are passed as libpq parameters, which pgx turns into its
+// TLS config.
+func connString(config db.ConnConfig) string {
+	query := url.Values{}
+	query.Set("sslmode", "prefer")
+	for key, value := range config.Params {
+		query.Set(key, value)
+	}
+	for key, value := range map[string]string{
+		"sslmode":     config.TLS.Mode,
+		"sslrootcert": config.TLS.CAFile,
+		"sslcert":     config.TLS.CertFile,
+		"sslkey":      config.TLS.KeyFile,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
 	}
 
-	return &Connection{
-		pool:    pool,
-		timeout: 30 * time.Second, // Default timeout
-	}, nil
+	u := url.URL{
+		Scheme:   "postgres",
+		Host:     net.JoinHostPort(config.Host, strconv.Itoa(config.Port)),
+		Path:     "/" + config.Database,
+		RawQuery: query.Encode(),
+	}
+	if config.Username != "" || config.Password != "" {
+		u.User = url.UserPassword(config.Username, config.Password)
+	}
+	return u.String()
 }
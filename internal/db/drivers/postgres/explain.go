@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/imran-vz/gosqlit/internal/debug"
+)
+
+// explainOutput is one element of EXPLAIN (FORMAT JSON)'s result array
+type explainOutput struct {
+	Plan          explainNode `json:"Plan"`
+	PlanningTime  float64     `json:"Planning Time"`
+	ExecutionTime float64     `json:"Execution Time"`
+}
+
+// explainNode is a plan node as EXPLAIN (FORMAT JSON) prints it
+type explainNode struct {
+	NodeType     string   `json:"Node Type"`
+	Strategy     string   `json:"Strategy"`
+	JoinType     string   `json:"Join Type"`
+	RelationName string   `json:"Relation Name"`
+	Alias        string   `json:"Alias"`
+	IndexName    string   `json:"Index Name"`
+	CTEName      string   `json:"CTE Name"`
+	FunctionName string   `json:"Function Name"`
+	HashCond     string   `json:"Hash Cond"`
+	MergeCond    string   `json:"Merge Cond"`
+	IndexCond    string   `json:"Index Cond"`
+	RecheckCond  string   `json:"Recheck Cond"`
+	JoinFilter   string   `json:"Join Filter"`
+	Filter       string   `json:"Filter"`
+	SortKey      []string `json:"Sort Key"`
+	GroupKey     []string `json:"Group Key"`
+
+	StartupCost       float64 `json:"Startup Cost"`
+	TotalCost         float64 `json:"Total Cost"`
+	PlanRows          float64 `json:"Plan Rows"`
+	ActualStartupTime float64 `json:"Actual Startup Time"`
+	ActualTotalTime   float64 `json:"Actual Total Time"`
+	ActualRows        float64 `json:"Actual Rows"`
+	ActualLoops       float64 `json:"Actual Loops"`
+
+	SharedHitBlocks   int64 `json:"Shared Hit Blocks"`
+	SharedReadBlocks  int64 `json:"Shared Read Blocks"`
+	TempReadBlocks    int64 `json:"Temp Read Blocks"`
+	TempWrittenBlocks int64 `json:"Temp Written Blocks"`
+
+	Plans []explainNode `json:"Plans"`
+}
+
+// Explain returns the plan of sql. With analyze the statement runs inside
+// a transaction, or a savepoint of the open one, that is rolled back.
+func (s *Session) Explain(ctx context.Context, sql string, analyze bool) (db.Plan, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	if err := s.reconnect(ctx); err != nil {
+		return db.Plan{}, err
+	}
+
+	s.run.mu.Lock()
+	defer s.run.mu.Unlock()
+
+	// A cursor's transaction is no place to run the statement
+	s.run.closeCursor()
+
+	options := "FORMAT JSON"
+	if analyze {
+		options += ", ANALYZE, BUFFERS"
+	}
+	stmt := fmt.Sprintf("EXPLAIN (%s) %s", options, strings.TrimSuffix(strings.TrimSpace(sql), ";"))
+
+	if !analyze {
+		return s.queryPlan(ctx, stmt, false)
+	}
+
+	begin, rollback := "BEGIN", []string{"ROLLBACK"}
+	switch s.run.conn.Conn().PgConn().TxStatus() {
+	case 'T':
+		begin = "SAVEPOINT gosqlit_explain"
+		rollback = []string{"ROLLBACK TO SAVEPOINT gosqlit_explain", "RELEASE SAVEPOINT gosqlit_explain"}
+	case 'E':
+		return db.Plan{}, fmt.Errorf("the current transaction is aborted, roll it back first")
+	}
+
+	if _, err := s.run.conn.Exec(ctx, begin); err != nil {
+		return db.Plan{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	plan, err := s.queryPlan(ctx, stmt, true)
+
+	// The statement's changes must not survive, even when ctx is done
+	rollbackCtx, cancelRollback := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelRollback()
+	for _, undo := range rollback {
+		if _, rbErr := s.run.conn.Exec(rollbackCtx, undo); rbErr != nil {
+			debug.LogError(rbErr, "postgres/explain_rollback")
+
+			// Closing the connection discards the transaction; reconnect
+			// replaces it on the next call
+			s.run.conn.Conn().Close(rollbackCtx)
+			if err == nil {
+				err = fmt.Errorf("failed to roll back: %w", rbErr)
+			}
+			break
+		}
+	}
+	return plan, err
+}
+
+// queryPlan runs an EXPLAIN (FORMAT JSON) statement and parses its plan.
+// Caller must hold s.run.mu.
+func (s *Session) queryPlan(ctx context.Context, stmt string, analyzed bool) (db.Plan, error) {
+	var out []byte
+	if err := s.run.conn.QueryRow(ctx, stmt).Scan(&out); err != nil {
+		return db.Plan{}, fmt.Errorf("explain failed: %w", err)
+	}
+	return parsePlan(out, analyzed)
+}
+
+// parsePlan converts EXPLAIN (FORMAT JSON) output into a plan
+func parsePlan(out []byte, analyzed bool) (db.Plan, error) {
+	var outputs []explainOutput
+	if err := json.Unmarshal(out, &outputs); err != nil {
+		return db.Plan{}, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if len(outputs) == 0 {
+		return db.Plan{}, fmt.Errorf("explain returned no plan")
+	}
+
+	return db.Plan{
+		Root:          outputs[0].Plan.planNode(),
+		Analyzed:      analyzed,
+		PlanningTime:  outputs[0].PlanningTime,
+		ExecutionTime: outputs[0].ExecutionTime,
+	}, nil
+}
+
+// planNode converts n and its children
+func (n explainNode) planNode() db.PlanNode {
+	node := db.PlanNode{
+		NodeType:          n.NodeType,
+		Relation:          n.relation(),
+		Detail:            n.detail(),
+		StartupCost:       n.StartupCost,
+		TotalCost:         n.TotalCost,
+		PlanRows:          n.PlanRows,
+		ActualStartupTime: n.ActualStartupTime,
+		ActualTotalTime:   n.ActualTotalTime,
+		ActualRows:        n.ActualRows,
+		ActualLoops:       n.ActualLoops,
+		SharedHitBlocks:   n.SharedHitBlocks,
+		SharedReadBlocks:  n.SharedReadBlocks,
+		TempReadBlocks:    n.TempReadBlocks,
+		TempWriteBlocks:   n.TempWrittenBlocks,
+	}
+	for _, child := range n.Plans {
+		node.Children = append(node.Children, child.planNode())
+	}
+	return node
+}
+
+// relation describes what the node reads, the way text EXPLAIN does:
+// "using index on table alias"
+func (n explainNode) relation() string {
+	var parts []string
+	if n.IndexName != "" {
+		parts = append(parts, "using "+n.IndexName)
+	}
+
+	name := n.RelationName
+	if name == "" {
+		name = n.CTEName
+	}
+	if name == "" {
+		name = n.FunctionName
+	}
+	if name != "" {
+		if n.Alias != "" && n.Alias != name {
+			name += " " + n.Alias
+		}
+		parts = append(parts, "on "+name)
+	}
+	return strings.Join(parts, " ")
+}
+
+// detail lists the node's strategy, join type, keys and conditions
+func (n explainNode) detail() string {
+	var parts []string
+	add := func(label, value string) {
+		if value != "" {
+			parts = append(parts, label+value)
+		}
+	}
+
+	add("", n.Strategy)
+	add("", n.JoinType)
+	add("Hash Cond: ", n.HashCond)
+	add("Merge Cond: ", n.MergeCond)
+	add("Index Cond: ", n.IndexCond)
+	add("Recheck Cond: ", n.RecheckCond)
+	add("Join Filter: ", n.JoinFilter)
+	add("Filter: ", n.Filter)
+	add("Sort Key: ", strings.Join(n.SortKey, ", "))
+	add("Group Key: ", strings.Join(n.GroupKey, ", "))
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"sync"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// maxNotices bounds the notices kept per connection between takes, so a
+// RAISE NOTICE in a long loop can't exhaust memory. Later ones are dropped.
+const maxNotices = 1000
+
+// noticeLog keeps the notices pgx hands to OnNotice, for the connections
+// pinned by sessions. Notices on other connections are dropped.
+type noticeLog struct {
+	mu     sync.Mutex
+	byConn map[*pgconn.PgConn][]db.Notice
+}
+
+func newNoticeLog() *noticeLog {
+	return &noticeLog{byConn: make(map[*pgconn.PgConn][]db.Notice)}
+}
+
+// receive is the connections' OnNotice handler
+func (l *noticeLog) receive(pgConn *pgconn.PgConn, notice *pgconn.Notice) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	notices, ok := l.byConn[pgConn]
+	if !ok || len(notices) >= maxNotices {
+		return
+	}
+	l.byConn[pgConn] = append(notices, db.Notice{
+		Severity: notice.Severity,
+		Code:     notice.Code,
+		Message:  notice.Message,
+		Detail:   notice.Detail,
+		Hint:     notice.Hint,
+		Where:    notice.Where,
+	})
+}
+
+// watch starts keeping the notices of pgConn
+func (l *noticeLog) watch(pgConn *pgconn.PgConn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.byConn[pgConn]; !ok {
+		l.byConn[pgConn] = nil
+	}
+}
+
+// forget stops keeping the notices of pgConn
+func (l *noticeLog) forget(pgConn *pgconn.PgConn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.byConn, pgConn)
+}
+
+// take returns the notices kept for pgConn and clears them
+func (l *noticeLog) take(pgConn *pgconn.PgConn) []db.Notice {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	notices, ok := l.byConn[pgConn]
+	if !ok {
+		return nil
+	}
+	l.byConn[pgConn] = nil
+	return notices
+}
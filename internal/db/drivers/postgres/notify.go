@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
+)
+
+// Listener implements db.Listener on a connection outside the pool, since
+// LISTEN lasts as long as the connection
+type Listener struct {
+	conn *pgx.Conn
+	mu   sync.Mutex // held while conn is in use
+
+	// Listen, Unlisten and Close interrupt a blocked wait for the
+	// connection; the wait resumes once none of them is pending
+	waitMu     sync.Mutex
+	idle       *sync.Cond
+	pending    int
+	cancelWait context.CancelFunc
+	closed     bool
+}
+
+// OpenListener opens a connection of its own for LISTEN, with the pool's
+// settings
+func (c *Connection) OpenListener(ctx context.Context) (db.Listener, error) {
+	config := c.pool.Config().ConnConfig
+
+	// A wait is interrupted to run LISTEN and UNLISTEN. There's no query to
+	// cancel on the server then, so the read is just cut short.
+	config.BuildContextWatcherHandler = func(pgConn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.DeadlineContextWatcherHandler{Conn: pgConn.Conn()}
+	}
+
+	conn, err := pgx.ConnectConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open listener connection: %w", err)
+	}
+
+	l := &Listener{conn: conn}
+	l.idle = sync.NewCond(&l.waitMu)
+	return l, nil
+}
+
+// Notify sends payload on channel
+func (c *Connection) Notify(ctx context.Context, channel, payload string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	if _, err := c.pool.Exec(ctx, "SELECT pg_notify($1, $2)", channel, payload); err != nil {
+		return fmt.Errorf("notify failed: %w", err)
+	}
+	return nil
+}
+
+// Listen starts receiving notifications on channel
+func (l *Listener) Listen(ctx context.Context, channel string) error {
+	return l.exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize())
+}
+
+// Unlisten stops receiving notifications on channel, or on all with "*"
+func (l *Listener) Unlisten(ctx context.Context, channel string) error {
+	if channel == "*" {
+		return l.exec(ctx, "UNLISTEN *")
+	}
+	return l.exec(ctx, "UNLISTEN "+pgx.Identifier{channel}.Sanitize())
+}
+
+// exec runs sql, interrupting a blocked wait
+func (l *Listener) exec(ctx context.Context, sql string) error {
+	return l.interrupt(func() error {
+		if _, err := l.conn.Exec(ctx, sql); err != nil {
+			return fmt.Errorf("%s failed: %w", sql, err)
+		}
+		return nil
+	})
+}
+
+// WaitForNotification blocks until a notification arrives or ctx is done.
+// It must not be called concurrently with itself.
+func (l *Listener) WaitForNotification(ctx context.Context) (db.Notification, error) {
+	for {
+		l.waitMu.Lock()
+		for l.pending > 0 {
+			l.idle.Wait()
+		}
+		if l.closed {
+			l.waitMu.Unlock()
+			return db.Notification{}, fmt.Errorf("listener is closed")
+		}
+		waitCtx, cancel := context.WithCancel(ctx)
+		l.cancelWait = cancel
+		l.mu.Lock()
+		l.waitMu.Unlock()
+
+		n, err := l.conn.WaitForNotification(waitCtx)
+
+		l.waitMu.Lock()
+		l.cancelWait = nil
+		l.waitMu.Unlock()
+		l.mu.Unlock()
+		cancel()
+
+		switch {
+		case err == nil:
+			return db.Notification{
+				Received: time.Now(),
+				Channel:  n.Channel,
+				Payload:  n.Payload,
+				PID:      int(n.PID),
+			}, nil
+		case ctx.Err() != nil:
+			return db.Notification{}, ctx.Err()
+		case errors.Is(err, context.Canceled):
+			// Interrupted to let Listen, Unlisten or Close have the
+			// connection
+			continue
+		default:
+			return db.Notification{}, fmt.Errorf("failed to wait for notification: %w", err)
+		}
+	}
+}
+
+// Close closes the listener's connection, ending any wait
+func (l *Listener) Close() error {
+	return l.interrupt(func() error {
+		l.waitMu.Lock()
+		l.closed = true
+		l.waitMu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return l.conn.Close(ctx)
+	})
+}
+
+// interrupt cancels a blocked wait and runs fn once the connection is free.
+// The wait doesn't resume until fn has returned.
+func (l *Listener) interrupt(fn func() error) error {
+	l.waitMu.Lock()
+	l.pending++
+	if l.cancelWait != nil {
+		l.cancelWait()
+	}
+	l.waitMu.Unlock()
+
+	defer func() {
+		l.waitMu.Lock()
+		l.pending--
+		if l.pending == 0 {
+			l.idle.Broadcast()
+		}
+		l.waitMu.Unlock()
+	}()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return fn()
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// rowIterator implements db.RowIterator over pgx rows
+type rowIterator struct {
+	rows pgx.Rows
+}
+
+// Columns returns the result columns with the types pgx knows by OID
+func (it *rowIterator) Columns() []db.Column {
+	return columnsFromFields(it.rows.FieldDescriptions(), it.rows.Conn().TypeMap())
+}
+
+// fields returns the raw field descriptions of the result
+func (it *rowIterator) fields() []pgconn.FieldDescription {
+	return it.rows.FieldDescriptions()
+}
+
+// Next advances to the next row
+func (it *rowIterator) Next() bool {
+	return it.rows.Next()
+}
+
+// Values returns the decoded values of the current row. JSON is kept as
+// text, since decoding it would round large numbers through float64.
+func (it *rowIterator) Values() ([]interface{}, error) {
+	values, err := it.rows.Values()
+	if err != nil {
+		return nil, err
+	}
+
+	raw := it.rows.RawValues()
+	for i, field := range it.rows.FieldDescriptions() {
+		if values[i] == nil {
+			continue
+		}
+		switch field.DataTypeOID {
+		case pgtype.JSONOID:
+			values[i] = string(raw[i])
+		case pgtype.JSONBOID:
+			// Binary jsonb starts with a version byte
+			text := raw[i]
+			if field.Format == pgtype.BinaryFormatCode && len(text) > 0 {
+				text = text[1:]
+			}
+			values[i] = string(text)
+		}
+	}
+	return values, nil
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *rowIterator) Err() error {
+	return it.rows.Err()
+}
+
+// Close releases the rows and their connection
+func (it *rowIterator) Close() error {
+	it.rows.Close()
+	return nil
+}
@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// querier is satisfied by both *pgxpool.Pool and *pgxpool.Conn
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// runner executes statements either on any pool connection or, for a
+// session, on one pinned connection. Row-returning statements are paged
+// through a server-side cursor.
+type runner struct {
+	pool    *pgxpool.Pool
+	conn    *pgxpool.Conn // pinned session connection, nil to use the pool
+	catalog *catalog
+
+	mu     sync.Mutex
+	cursor *cursor // open cursor of the latest paged query, if any
+}
+
+// query returns up to limit rows of sql starting at offset. Asking for the
+// next page of the same SQL continues where the previous call stopped
+// instead of re-running the query.
+func (r *runner) query(ctx context.Context, sql string, limit int, offset int) (db.QueryResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limit <= 0 {
+		r.closeCursor()
+		return r.queryAll(ctx, sql)
+	}
+
+	// Next page of the current result
+	if cur := r.cursor; cur != nil && offset > 0 && cur.sql == sql && cur.pos == offset {
+		return r.fetchPage(ctx, limit)
+	}
+
+	r.closeCursor()
+
+	cur, err := r.openCursor(ctx, sql)
+	if errors.Is(err, errCursorUnsupported) {
+		return r.queryAll(ctx, sql)
+	}
+	if err != nil {
+		return db.QueryResult{}, err
+	}
+	r.cursor = cur
+
+	if offset > 0 {
+		if err := cur.skip(ctx, offset); err != nil {
+			r.closeCursor()
+			return db.QueryResult{}, err
+		}
+	}
+
+	return r.fetchPage(ctx, limit)
+}
+
+// openCursor declares a cursor on the session connection, or on a
+// connection taken from the pool for as long as the cursor stays open
+func (r *runner) openCursor(ctx context.Context, sql string) (*cursor, error) {
+	if r.conn != nil {
+		return openCursor(ctx, r.conn.Conn(), sql)
+	}
+
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	cur, err := openCursor(ctx, conn.Conn(), sql)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+	cur.release = conn.Release
+	return cur, nil
+}
+
+// fetchPage reads the next page from the open cursor, closing it once the
+// result is exhausted or broken
+func (r *runner) fetchPage(ctx context.Context, limit int) (db.QueryResult, error) {
+	result, err := r.cursor.fetch(ctx, limit, r.catalog)
+	if err != nil || !result.HasMore {
+		r.closeCursor()
+	}
+	return result, err
+}
+
+// closeCursor releases the open cursor, if any. Caller must hold r.mu.
+func (r *runner) closeCursor() {
+	if r.cursor != nil {
+		r.cursor.close()
+		r.cursor = nil
+	}
+}
+
+// close releases the open cursor, if any
+func (r *runner) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closeCursor()
+}
+
+// queryAll executes SQL and reads every row. Caller must hold r.mu.
+func (r *runner) queryAll(ctx context.Context, sql string) (db.QueryResult, error) {
+	rows, err := r.querier().Query(ctx, sql)
+	if err != nil {
+		return db.QueryResult{}, fmt.Errorf("query failed: %w", err)
+	}
+	it := &rowIterator{rows: rows}
+	defer it.Close()
+
+	result, err := db.CollectRows(it, 0)
+	if err != nil {
+		return db.QueryResult{}, err
+	}
+	r.catalog.describe(ctx, result.Columns, it.fields())
+	return result, nil
+}
+
+// queryRows executes SQL and streams its rows
+func (r *runner) queryRows(ctx context.Context, sql string) (db.RowIterator, error) {
+	// A session's cursor holds the connection the rows would be read from
+	if r.conn != nil {
+		r.close()
+	}
+
+	rows, err := r.querier().Query(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	return &rowIterator{rows: rows}, nil
+}
+
+// exec executes a statement that returns no rows
+func (r *runner) exec(ctx context.Context, sql string) (db.ExecResult, error) {
+	// The open cursor's transaction would block DDL on the tables it reads
+	r.close()
+
+	tag, err := r.querier().Exec(ctx, sql)
+	if err != nil {
+		return db.ExecResult{}, fmt.Errorf("exec failed: %w", err)
+	}
+
+	return db.ExecResult{
+		CommandTag:   tag.String(),
+		RowsAffected: tag.RowsAffected(),
+	}, nil
+}
+
+// querier returns where statements run
+func (r *runner) querier() querier {
+	if r.conn != nil {
+		return r.conn
+	}
+	return r.pool
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/imran-vz/gosqlit/internal/debug"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Session implements db.Session on a pinned pool connection
+type Session struct {
+	timeout time.Duration
+	run     *runner
+	notices *noticeLog
+}
+
+// Query executes SQL query, paging through a cursor like Connection.Query
+func (s *Session) Query(ctx context.Context, sql string, limit int, offset int) (db.QueryResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	if err := s.reconnect(ctx); err != nil {
+		return db.QueryResult{}, err
+	}
+	return s.run.query(ctx, sql, limit, offset)
+}
+
+// QueryRows executes SQL and streams its rows. The session is busy until
+// the iterator is closed.
+func (s *Session) QueryRows(ctx context.Context, sql string) (db.RowIterator, error) {
+	if err := s.reconnect(ctx); err != nil {
+		return nil, err
+	}
+	return s.run.queryRows(ctx, sql)
+}
+
+// Exec executes a statement that returns no rows
+func (s *Session) Exec(ctx context.Context, sql string) (db.ExecResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	if err := s.reconnect(ctx); err != nil {
+		return db.ExecResult{}, err
+	}
+	return s.run.exec(ctx, sql)
+}
+
+// TxStatus reports the transaction state the server last sent. A cursor's
+// own transaction isn't the user's, so it counts as idle.
+func (s *Session) TxStatus() db.TxStatus {
+	s.run.mu.Lock()
+	defer s.run.mu.Unlock()
+
+	if s.run.cursor != nil && s.run.cursor.ownTx {
+		return db.TxIdle
+	}
+
+	switch s.run.conn.Conn().PgConn().TxStatus() {
+	case 'T':
+		return db.TxActive
+	case 'E':
+		return db.TxFailed
+	default:
+		return db.TxIdle
+	}
+}
+
+// TakeNotices returns the notices the server sent since the last call
+func (s *Session) TakeNotices() []db.Notice {
+	s.run.mu.Lock()
+	defer s.run.mu.Unlock()
+
+	return s.notices.take(s.run.conn.Conn().PgConn())
+}
+
+// Close rolls back any open transaction, resets the connection and returns
+// it to the pool
+func (s *Session) Close() error {
+	s.run.close()
+	s.notices.forget(s.run.conn.Conn().PgConn())
+	releaseReset(s.run.conn)
+	return nil
+}
+
+// reconnect replaces the pinned connection once it has been closed, such as
+// after a network error. Its transaction is lost with it.
+func (s *Session) reconnect(ctx context.Context) error {
+	s.run.mu.Lock()
+	defer s.run.mu.Unlock()
+
+	if !s.run.conn.Conn().IsClosed() {
+		return nil
+	}
+
+	conn, err := s.run.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	s.run.cursor = nil
+	s.notices.forget(s.run.conn.Conn().PgConn())
+	releaseReset(s.run.conn)
+	s.run.conn = conn
+	s.notices.watch(conn.Conn().PgConn())
+	return nil
+}
+
+// releaseReset returns a session's connection to the pool without the
+// session's settings, role, temp tables, prepared statements and LISTENs,
+// so the next pool user doesn't pick them up. A connection that can't be
+// reset is closed instead.
+func releaseReset(conn *pgxpool.Conn) {
+	defer conn.Release()
+
+	pgConn := conn.Conn()
+	if pgConn.IsClosed() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// DISCARD ALL can't run inside a transaction
+	if pgConn.PgConn().TxStatus() != 'I' {
+		if _, err := pgConn.Exec(ctx, "ROLLBACK"); err != nil {
+			debug.LogError(err, "postgres/session_rollback")
+		}
+	}
+	if _, err := pgConn.Exec(ctx, "DISCARD ALL"); err != nil {
+		debug.LogError(err, "postgres/session_reset")
+		pgConn.Close(ctx)
+	}
+}
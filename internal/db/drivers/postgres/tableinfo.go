@@ -0,0 +1,243 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/jackc/pgx/v5"
+)
+
+// referentialActions names pg_constraint's confupdtype and confdeltype codes
+var referentialActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+// GetTableInfo returns table metadata: columns, constraints, indexes and
+// comments, read from the system catalogs
+func (c *Connection) GetTableInfo(ctx context.Context, schema, table string) (db.TableInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var info db.TableInfo
+	var oid uint32
+	err := c.pool.QueryRow(ctx, `
+		SELECT c.oid, COALESCE(obj_description(c.oid, 'pg_class'), '')
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2
+	`, schema, table).Scan(&oid, &info.Comment)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.TableInfo{}, fmt.Errorf("table %s.%s not found", schema, table)
+	}
+	if err != nil {
+		return db.TableInfo{}, fmt.Errorf("failed to get table info: %w", err)
+	}
+
+	if info.Columns, err = c.tableColumns(ctx, oid); err != nil {
+		return db.TableInfo{}, err
+	}
+	if err := c.tableConstraints(ctx, oid, &info); err != nil {
+		return db.TableInfo{}, err
+	}
+	if info.Indexes, err = c.tableIndexes(ctx, oid); err != nil {
+		return db.TableInfo{}, err
+	}
+
+	setColumnKeys(&info)
+	return info, nil
+}
+
+// tableColumns reads a table's columns with their defaults, identity and
+// generation expressions and comments
+func (c *Connection) tableColumns(ctx context.Context, oid uint32) ([]db.ColumnInfo, error) {
+	// attidentity is new in PostgreSQL 10 and attgenerated in 12
+	version, err := c.serverVersionNum(ctx)
+	if err != nil {
+		return nil, err
+	}
+	identity, generated := "a.attidentity::text", "a.attgenerated::text"
+	if version < 100000 {
+		identity = "''::text"
+	}
+	if version < 120000 {
+		generated = "''::text"
+	}
+
+	rows, err := c.pool.Query(ctx, `
+		SELECT a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull,
+			COALESCE(pg_get_expr(d.adbin, d.adrelid), ''),
+			`+identity+`, `+generated+`,
+			COALESCE(col_description(a.attrelid, a.attnum), '')
+		FROM pg_attribute a
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = $1 AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum
+	`, oid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []db.ColumnInfo
+	for rows.Next() {
+		var col db.ColumnInfo
+		var identity, generated string
+		if err := rows.Scan(&col.Name, &col.Type, &col.Nullable, &col.Default, &identity, &generated, &col.Comment); err != nil {
+			return nil, fmt.Errorf("failed to scan column: %w", err)
+		}
+
+		switch identity {
+		case "a":
+			col.Identity = "ALWAYS"
+		case "d":
+			col.Identity = "BY DEFAULT"
+		}
+		// A generated column's expression is stored as its default
+		if generated == "s" {
+			col.Generated, col.Default = col.Default, ""
+		}
+
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+// tableConstraints reads a table's primary key, unique, foreign key and
+// check constraints into info
+func (c *Connection) tableConstraints(ctx context.Context, oid uint32, info *db.TableInfo) error {
+	// Column arrays keep the constraint's column order
+	rows, err := c.pool.Query(ctx, `
+		SELECT con.conname, con.contype::text, pg_get_constraintdef(con.oid),
+			ARRAY(
+				SELECT a.attname::text FROM pg_attribute a
+				WHERE a.attrelid = con.conrelid AND a.attnum = ANY(con.conkey)
+				ORDER BY array_position(con.conkey, a.attnum)),
+			COALESCE(rn.nspname::text, ''), COALESCE(rc.relname::text, ''),
+			ARRAY(
+				SELECT a.attname::text FROM pg_attribute a
+				WHERE a.attrelid = con.confrelid AND a.attnum = ANY(con.confkey)
+				ORDER BY array_position(con.confkey, a.attnum)),
+			con.confupdtype::text, con.confdeltype::text
+		FROM pg_constraint con
+		LEFT JOIN pg_class rc ON rc.oid = con.confrelid
+		LEFT JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+		WHERE con.conrelid = $1 AND con.contype IN ('p', 'u', 'f', 'c')
+		ORDER BY con.conname
+	`, oid)
+	if err != nil {
+		return fmt.Errorf("failed to get constraints: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var constraint db.Constraint
+		var kind, refSchema, refTable, onUpdate, onDelete string
+		var refColumns []string
+		if err := rows.Scan(&constraint.Name, &kind, &constraint.Definition, &constraint.Columns,
+			&refSchema, &refTable, &refColumns, &onUpdate, &onDelete); err != nil {
+			return fmt.Errorf("failed to scan constraint: %w", err)
+		}
+
+		switch kind {
+		case "p":
+			info.PrimaryKey = &constraint
+		case "u":
+			info.Uniques = append(info.Uniques, constraint)
+		case "c":
+			info.Checks = append(info.Checks, constraint)
+		case "f":
+			info.ForeignKeys = append(info.ForeignKeys, db.ForeignKey{
+				Name:       constraint.Name,
+				Columns:    constraint.Columns,
+				RefSchema:  refSchema,
+				RefTable:   refTable,
+				RefColumns: refColumns,
+				OnUpdate:   referentialActions[onUpdate],
+				OnDelete:   referentialActions[onDelete],
+				Definition: constraint.Definition,
+			})
+		}
+	}
+	return rows.Err()
+}
+
+// tableIndexes reads a table's indexes. Key columns that are expressions
+// are given as pg_get_indexdef prints them.
+func (c *Connection) tableIndexes(ctx context.Context, oid uint32) ([]db.Index, error) {
+	// indnkeyatts is new in PostgreSQL 11; before INCLUDE columns every
+	// column was a key column
+	version, err := c.serverVersionNum(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keyAtts := "i.indnkeyatts"
+	if version < 110000 {
+		keyAtts = "i.indnatts"
+	}
+
+	rows, err := c.pool.Query(ctx, `
+		SELECT ic.relname, i.indisunique, i.indisprimary, am.amname::text,
+			pg_get_indexdef(i.indexrelid),
+			ARRAY(
+				SELECT COALESCE(a.attname::text, pg_get_indexdef(i.indexrelid, k, true))
+				FROM generate_series(1, `+keyAtts+`) k
+				LEFT JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = i.indkey[k - 1] AND a.attnum > 0
+				ORDER BY k)
+		FROM pg_index i
+		JOIN pg_class ic ON ic.oid = i.indexrelid
+		JOIN pg_am am ON am.oid = ic.relam
+		WHERE i.indrelid = $1
+		ORDER BY ic.relname
+	`, oid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []db.Index
+	for rows.Next() {
+		var index db.Index
+		if err := rows.Scan(&index.Name, &index.Unique, &index.Primary, &index.Method, &index.Definition, &index.Columns); err != nil {
+			return nil, fmt.Errorf("failed to scan index: %w", err)
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, rows.Err()
+}
+
+// setColumnKeys sets each column's Key the way MySQL reports COLUMN_KEY:
+// PRI for primary key columns, UNI for columns unique on their own and MUL
+// for the first column of a non-unique index
+func setColumnKeys(info *db.TableInfo) {
+	keys := make(map[string]string)
+	for _, index := range info.Indexes {
+		if !index.Unique && len(index.Columns) > 0 && keys[index.Columns[0]] == "" {
+			keys[index.Columns[0]] = "MUL"
+		}
+	}
+	for _, index := range info.Indexes {
+		if index.Unique && len(index.Columns) == 1 {
+			keys[index.Columns[0]] = "UNI"
+		}
+	}
+	for _, unique := range info.Uniques {
+		if len(unique.Columns) == 1 {
+			keys[unique.Columns[0]] = "UNI"
+		}
+	}
+	if info.PrimaryKey != nil {
+		for _, column := range info.PrimaryKey.Columns {
+			keys[column] = "PRI"
+		}
+	}
+
+	for i := range info.Columns {
+		info.Columns[i].Key = keys[info.Columns[i].Name]
+	}
+}
@@ -0,0 +1,258 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// Connection implements db.Connection for SQLite
+type Connection struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// Query executes SQL query. Results are read in full since the database is
+// local and the single connection can't be held by an open result.
+func (c *Connection) Query(ctx context.Context, query string, limit int, offset int) (db.QueryResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	it, err := c.QueryRows(ctx, query)
+	if err != nil {
+		return db.QueryResult{}, err
+	}
+	defer it.Close()
+
+	return db.CollectRows(it, 0)
+}
+
+// QueryRows executes SQL and streams its rows. The connection timeout is not
+// applied; cancel ctx to stop. The database is unavailable to other calls
+// until the iterator is closed.
+func (c *Connection) QueryRows(ctx context.Context, query string) (db.RowIterator, error) {
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	it, err := newRowIterator(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	return it, nil
+}
+
+// Exec executes a statement that returns no rows
+func (c *Connection) Exec(ctx context.Context, query string) (db.ExecResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	res, err := c.db.ExecContext(ctx, query)
+	if err != nil {
+		return db.ExecResult{}, fmt.Errorf("exec failed: %w", err)
+	}
+
+	// SQLite keeps the last count across statements that change no rows
+	var affected int64
+	switch db.FirstKeyword(query) {
+	case "INSERT", "UPDATE", "DELETE", "REPLACE", "WITH":
+		affected, _ = res.RowsAffected()
+	}
+
+	return db.ExecResult{
+		CommandTag:   db.CommandTag(query, affected),
+		RowsAffected: affected,
+	}, nil
+}
+
+// ListSchemas returns the main, temp and attached databases
+func (c *Connection) ListSchemas(ctx context.Context) ([]db.Schema, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rows, err := c.db.QueryContext(ctx, "SELECT name FROM pragma_database_list ORDER BY seq")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan schema: %w", err)
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+
+	// The pool holds a single connection, so rows must be closed before
+	// querying each schema's tables
+	var schemas []db.Schema
+	for _, name := range names {
+		tables, err := c.ListTables(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tables for schema %s: %w", name, err)
+		}
+
+		schemas = append(schemas, db.Schema{
+			Name:   name,
+			Tables: tables,
+		})
+	}
+
+	return schemas, nil
+}
+
+// ListTables returns tables and views in a schema
+func (c *Connection) ListTables(ctx context.Context, schema string) ([]db.Table, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+		SELECT name, type
+		FROM %s.sqlite_master
+		WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%%'
+		ORDER BY name
+	`, quoteIdent(schema))
+
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []db.Table
+	for rows.Next() {
+		var tableName, tableType string
+		if err := rows.Scan(&tableName, &tableType); err != nil {
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+
+		table := db.Table{
+			Name:   tableName,
+			Schema: schema,
+		}
+		if tableType == "view" {
+			table.Kind = db.ObjectView
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
+
+// GetTableInfo returns table metadata
+func (c *Connection) GetTableInfo(ctx context.Context, schema, table string) (db.TableInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// Columns referencing other tables
+	foreignKeys, err := c.queryColumnSet(ctx,
+		`SELECT "from" FROM pragma_foreign_key_list(?, ?)`, table, schema)
+	if err != nil {
+		return db.TableInfo{}, fmt.Errorf("failed to get foreign keys: %w", err)
+	}
+
+	// Columns covered on their own by a UNIQUE constraint or unique index
+	unique, err := c.queryColumnSet(ctx, `
+		SELECT MIN(ii.name)
+		FROM pragma_index_list(?, ?) il
+		JOIN pragma_index_info(il.name, ?) ii
+		WHERE il."unique" = 1 AND il.origin != 'pk'
+		GROUP BY il.name
+		HAVING COUNT(*) = 1
+	`, table, schema, schema)
+	if err != nil {
+		return db.TableInfo{}, fmt.Errorf("failed to get unique indexes: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx,
+		`SELECT name, type, "notnull", pk FROM pragma_table_info(?, ?) ORDER BY cid`, table, schema)
+	if err != nil {
+		return db.TableInfo{}, fmt.Errorf("failed to get table info: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []db.ColumnInfo
+	for rows.Next() {
+		var col db.ColumnInfo
+		var notNull bool
+		var pk int
+
+		if err := rows.Scan(&col.Name, &col.Type, &notNull, &pk); err != nil {
+			return db.TableInfo{}, fmt.Errorf("failed to scan column: %w", err)
+		}
+
+		col.Nullable = !notNull
+		switch {
+		case pk > 0:
+			col.Key = "PRI"
+		case unique[col.Name]:
+			col.Key = "UNI"
+		case foreignKeys[col.Name]:
+			col.Key = "MUL"
+		}
+
+		columns = append(columns, col)
+	}
+
+	if err := rows.Err(); err != nil {
+		return db.TableInfo{}, fmt.Errorf("rows error: %w", err)
+	}
+
+	return db.TableInfo{
+		Columns: columns,
+	}, nil
+}
+
+// Ping checks if connection is alive
+func (c *Connection) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	return c.db.PingContext(ctx)
+}
+
+// Close closes the connection
+func (c *Connection) Close() error {
+	return c.db.Close()
+}
+
+// SetTimeout sets query timeout
+func (c *Connection) SetTimeout(duration time.Duration) {
+	c.timeout = duration
+}
+
+// queryColumnSet runs a single-column query and returns its values as a set
+func (c *Connection) queryColumnSet(ctx context.Context, query string, args ...interface{}) (map[string]bool, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	set := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		set[name] = true
+	}
+
+	return set, rows.Err()
+}
+
+// quoteIdent quotes an SQLite identifier
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,47 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// ObjectDDL returns the statements that created a table or view, as SQLite
+// stores them, followed by those of its indexes and triggers
+func (c *Connection) ObjectDDL(ctx context.Context, object db.Table) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// Automatic indexes, e.g. for UNIQUE constraints, have no SQL
+	query := fmt.Sprintf(`
+		SELECT sql
+		FROM %s.sqlite_master
+		WHERE tbl_name = ? AND sql IS NOT NULL
+		ORDER BY name != tbl_name, type, name
+	`, quoteIdent(object.Schema))
+
+	rows, err := c.db.QueryContext(ctx, query, object.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get DDL: %w", err)
+	}
+	defer rows.Close()
+
+	var statements []string
+	for rows.Next() {
+		var statement string
+		if err := rows.Scan(&statement); err != nil {
+			return "", fmt.Errorf("failed to scan DDL: %w", err)
+		}
+		statements = append(statements, strings.TrimSuffix(strings.TrimSpace(statement), ";")+";\n")
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to get DDL: %w", err)
+	}
+	if len(statements) == 0 {
+		return "", fmt.Errorf("%s.%s not found", object.Schema, object.Name)
+	}
+
+	return strings.Join(statements, "\n"), nil
+}
@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+	_ "modernc.org/sqlite"
+)
+
+// Driver implements db.Driver for SQLite
+type Driver struct{}
+
+// Name returns driver name
+func (d *Driver) Name() string {
+	return "sqlite"
+}
+
+// DefaultPort returns 0, SQLite databases are local files
+func (d *Driver) DefaultPort() int {
+	return 0
+}
+
+// Fields returns the connection settings SQLite takes, just the file
+func (d *Driver) Fields() []db.Field {
+	return []db.Field{
+		{Key: db.FieldDatabase, Label: "Database File", Type: db.FieldPath},
+		{Key: db.FieldParams, Label: "Parameters", Optional: true},
+		{Key: db.FieldQueryTimeout, Label: "Query Timeout (s)", Type: db.FieldNumber, Default: "30", Optional: true},
+		{Key: db.FieldLockTimeout, Label: "Busy Timeout (s)", Type: db.FieldNumber, Default: "5", Optional: true},
+	}
+}
+
+// Schemes returns the connection URL schemes SQLite accepts
+func (d *Driver) Schemes() []string {
+	return []string{"sqlite", "sqlite3", "file"}
+}
+
+// Capabilities returns what SQLite supports. Attached databases are listed
+// as schemas, but there are no namespaces within one.
+func (d *Driver) Capabilities() db.Capabilities {
+	return db.Capabilities{
+		Transactions: true,
+		Cancel:       true,
+	}
+}
+
+// Connect opens the SQLite database file named by config.Database
+func (d *Driver) Connect(ctx context.Context, config db.ConnConfig) (db.Connection, error) {
+	path := config.Database
+	if path == "" {
+		return nil, fmt.Errorf("database file path is required")
+	}
+
+	// Don't let a typo silently create an empty database
+	if path != ":memory:" {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("failed to open database file: %w", err)
+		}
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	// Waiting for another process's lock is SQLite's lock timeout
+	busyTimeout := 5 * time.Second
+	if config.LockTimeout > 0 {
+		busyTimeout = config.LockTimeout
+	}
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	for key, value := range config.Params {
+		// Pragmas add to the defaults; later ones win
+		if key == "_pragma" {
+			params.Add(key, value)
+		} else {
+			params.Set(key, value)
+		}
+	}
+	dsn := "file:" + (&url.URL{Path: path}).EscapedPath() + "?" + params.Encode()
+
+	sqlDB, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Attached databases and temp tables only exist on the connection that
+	// created them, so keep a single connection for the whole session
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetMaxIdleConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+
+	// Test connection
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &Connection{
+		db:      sqlDB,
+		timeout: db.DefaultQueryTimeout,
+	}, nil
+}
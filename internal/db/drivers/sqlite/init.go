@@ -0,0 +1,7 @@
+package sqlite
+
+import "github.com/imran-vz/gosqlit/internal/db"
+
+func init() {
+	db.RegisterDriver("sqlite", &Driver{})
+}
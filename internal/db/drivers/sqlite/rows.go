@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// rowIterator implements db.RowIterator over database/sql rows
+type rowIterator struct {
+	rows    *sql.Rows
+	columns []db.Column
+}
+
+// newRowIterator wraps rows, reading their column metadata up front
+func newRowIterator(rows *sql.Rows) (*rowIterator, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	// Only the declared type is known; expressions have none, and the
+	// driver reports every column as nullable
+	columns := make([]db.Column, len(columnTypes))
+	for i, ct := range columnTypes {
+		columns[i] = db.Column{
+			Name:     ct.Name(),
+			TypeName: ct.DatabaseTypeName(),
+			Kind:     db.KindForTypeName(ct.DatabaseTypeName()),
+		}
+	}
+
+	return &rowIterator{rows: rows, columns: columns}, nil
+}
+
+// Columns returns the result columns
+func (it *rowIterator) Columns() []db.Column {
+	return it.columns
+}
+
+// Next advances to the next row
+func (it *rowIterator) Next() bool {
+	return it.rows.Next()
+}
+
+// Values returns the values of the current row
+func (it *rowIterator) Values() ([]interface{}, error) {
+	values := make([]interface{}, len(it.columns))
+	ptrs := make([]interface{}, len(it.columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := it.rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *rowIterator) Err() error {
+	return it.rows.Err()
+}
+
+// Close releases the rows
+func (it *rowIterator) Close() error {
+	return it.rows.Close()
+}
@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// Session implements db.Session. The connection only has one database
+// handle, so a session shares it and its transaction is seen by every other
+// call on the connection.
+type Session struct {
+	c      *Connection
+	status db.TxStatus
+}
+
+// OpenSession starts a session on the shared database handle
+func (c *Connection) OpenSession(ctx context.Context) (db.Session, error) {
+	return &Session{c: c}, nil
+}
+
+// Query executes SQL query
+func (s *Session) Query(ctx context.Context, query string, limit int, offset int) (db.QueryResult, error) {
+	result, err := s.c.Query(ctx, query, limit, offset)
+	if err == nil {
+		s.status = db.TrackTxStatus(s.status, query)
+	}
+	return result, err
+}
+
+// QueryRows executes SQL and streams its rows
+func (s *Session) QueryRows(ctx context.Context, query string) (db.RowIterator, error) {
+	it, err := s.c.QueryRows(ctx, query)
+	if err == nil {
+		s.status = db.TrackTxStatus(s.status, query)
+	}
+	return it, err
+}
+
+// Exec executes a statement that returns no rows
+func (s *Session) Exec(ctx context.Context, query string) (db.ExecResult, error) {
+	result, err := s.c.Exec(ctx, query)
+	if err == nil {
+		s.status = db.TrackTxStatus(s.status, query)
+	}
+	return result, err
+}
+
+// TxStatus reports whether a transaction was begun and not yet ended
+func (s *Session) TxStatus() db.TxStatus {
+	return s.status
+}
+
+// Close rolls back an open transaction, which would otherwise stay open on
+// the shared handle
+func (s *Session) Close() error {
+	if s.status == db.TxIdle {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.c.db.ExecContext(ctx, "ROLLBACK")
+	s.status = db.TxIdle
+	return err
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+)
+
+// Plan is the query plan of a statement, with run-time figures when it was
+// explained with ANALYZE
+type Plan struct {
+	Root          PlanNode
+	Analyzed      bool
+	PlanningTime  float64 // ms
+	ExecutionTime float64 // ms, analyzed plans only
+}
+
+// PlanNode is one step of a query plan. Costs are in the planner's
+// arbitrary units; actual figures are per loop, as the server reports them.
+type PlanNode struct {
+	NodeType string // e.g. "Seq Scan", "Hash Join"
+	Relation string // table, index or CTE the node reads, if any
+	Detail   string // join type, conditions and filters
+
+	StartupCost float64
+	TotalCost   float64
+	PlanRows    float64
+
+	ActualStartupTime float64 // ms
+	ActualTotalTime   float64 // ms
+	ActualRows        float64
+	ActualLoops       float64
+
+	SharedHitBlocks  int64
+	SharedReadBlocks int64
+	TempReadBlocks   int64
+	TempWriteBlocks  int64
+
+	Children []PlanNode
+}
+
+// TotalTime is the time spent in the node and below, over all its loops
+func (n PlanNode) TotalTime() float64 {
+	return n.ActualTotalTime * n.ActualLoops
+}
+
+// SelfTime is the time spent in the node itself, without its children
+func (n PlanNode) SelfTime() float64 {
+	self := n.TotalTime()
+	for _, child := range n.Children {
+		self -= child.TotalTime()
+	}
+	return max(self, 0)
+}
+
+// SelfCost is the node's estimated cost without its children's
+func (n PlanNode) SelfCost() float64 {
+	self := n.TotalCost
+	for _, child := range n.Children {
+		self -= child.TotalCost
+	}
+	return max(self, 0)
+}
+
+// Explainer is implemented by sessions that can show a statement's plan.
+// With analyze the statement is run, inside a transaction that is rolled
+// back so its changes are discarded.
+type Explainer interface {
+	Explain(ctx context.Context, sql string, analyze bool) (Plan, error)
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"strconv"
+	"time"
+)
+
+// FieldType is the kind of input a connection field takes
+type FieldType int
+
+const (
+	FieldText FieldType = iota
+	FieldNumber
+	FieldPath // a file on the local machine
+)
+
+// Connection field keys, naming the ConnConfig setting a field fills in
+const (
+	FieldHost     = "host"
+	FieldPort     = "port"
+	FieldUsername = "username"
+	FieldPassword = "password"
+	FieldDatabase = "database"
+	FieldParams   = "params" // URL query string of driver parameters
+
+	FieldTLSMode       = "tls_mode"
+	FieldTLSCAFile     = "tls_ca_file"
+	FieldTLSCertFile   = "tls_cert_file"
+	FieldTLSKeyFile    = "tls_key_file"
+	FieldTLSServerName = "tls_server_name"
+
+	FieldSSHHost       = "ssh_host"
+	FieldSSHUser       = "ssh_user"
+	FieldSSHKeyFile    = "ssh_key_file"
+	FieldSSHKnownHosts = "ssh_known_hosts"
+
+	FieldQueryTimeout     = "query_timeout" // seconds, set with Connection.SetTimeout
+	FieldConnectTimeout   = "connect_timeout"
+	FieldStatementTimeout = "statement_timeout"
+	FieldLockTimeout      = "lock_timeout"
+	FieldMaxConns         = "max_conns"
+	FieldMinConns         = "min_conns"
+)
+
+// Field describes a connection setting a driver takes, in the order the
+// connection form shows them
+type Field struct {
+	Key      string // one of the Field* keys
+	Label    string
+	Type     FieldType
+	Default  string
+	Options  []string // allowed values, picked from a list
+	Secret   bool     // masked while typed
+	Optional bool
+}
+
+// TLSFields returns the connection fields for TLSConfig settings
+func TLSFields(defaultMode string) []Field {
+	return []Field{
+		{Key: FieldTLSMode, Label: "SSL Mode", Default: defaultMode, Options: TLSModes},
+		{Key: FieldTLSCAFile, Label: "CA File", Type: FieldPath, Optional: true},
+		{Key: FieldTLSCertFile, Label: "Client Cert", Type: FieldPath, Optional: true},
+		{Key: FieldTLSKeyFile, Label: "Client Key", Type: FieldPath, Optional: true},
+		{Key: FieldTLSServerName, Label: "TLS Server Name", Optional: true},
+	}
+}
+
+// SSHFields returns the connection fields for SSHConfig settings
+func SSHFields() []Field {
+	return []Field{
+		{Key: FieldSSHHost, Label: "SSH Host", Optional: true},
+		{Key: FieldSSHUser, Label: "SSH User", Optional: true},
+		{Key: FieldSSHKeyFile, Label: "SSH Key File", Type: FieldPath, Optional: true},
+		{Key: FieldSSHKnownHosts, Label: "SSH Known Hosts", Type: FieldPath, Optional: true},
+	}
+}
+
+// LimitFields returns the connection fields for timeouts and pool size
+func LimitFields() []Field {
+	return []Field{
+		{Key: FieldQueryTimeout, Label: "Query Timeout (s)", Type: FieldNumber, Default: seconds(DefaultQueryTimeout), Optional: true},
+		{Key: FieldConnectTimeout, Label: "Connect Timeout (s)", Type: FieldNumber, Default: seconds(DefaultConnectTimeout), Optional: true},
+		{Key: FieldStatementTimeout, Label: "Stmt Timeout (s)", Type: FieldNumber, Optional: true},
+		{Key: FieldLockTimeout, Label: "Lock Timeout (s)", Type: FieldNumber, Optional: true},
+		{Key: FieldMaxConns, Label: "Max Connections", Type: FieldNumber, Default: strconv.Itoa(DefaultMaxConns), Optional: true},
+		{Key: FieldMinConns, Label: "Min Connections", Type: FieldNumber, Default: strconv.Itoa(DefaultMinConns), Optional: true},
+	}
+}
+
+// seconds formats d as a whole number of seconds
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(d / time.Second))
+}
+
+// Capabilities describes what a driver supports
+type Capabilities struct {
+	Transactions bool // BEGIN/COMMIT/ROLLBACK on a session
+	Schemas      bool // namespaces within a database
+	Explain      bool // EXPLAIN plans the plan viewer can show
+	Cancel       bool // running statements can be cancelled
+	Activity     bool // server backends and their lock waits can be listed, cancelled and terminated
+	Notify       bool // notifications can be listened for and sent
+}
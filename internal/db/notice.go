@@ -0,0 +1,28 @@
+package db
+
+// Notice is a message the server sent while running a statement, such as
+// from RAISE NOTICE or VACUUM VERBOSE
+type Notice struct {
+	Severity string // NOTICE, WARNING, INFO, ...
+	Code     string // SQLSTATE
+	Message  string
+	Detail   string
+	Hint     string
+	Where    string // context, e.g. the PL/pgSQL function and line
+}
+
+// NoticeCollector is implemented by sessions that keep the notices their
+// server sends
+type NoticeCollector interface {
+	// TakeNotices returns the notices received since the last call
+	TakeNotices() []Notice
+}
+
+// TakeNotices returns the notices conn received since they were last taken,
+// if it collects them
+func TakeNotices(conn Executor) []Notice {
+	if collector, ok := conn.(NoticeCollector); ok {
+		return collector.TakeNotices()
+	}
+	return nil
+}
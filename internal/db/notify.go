@@ -0,0 +1,30 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// Notification is an asynchronous message sent with NOTIFY
+type Notification struct {
+	Received time.Time
+	Channel  string
+	Payload  string
+	PID      int // backend that sent it
+}
+
+// Listener receives notifications on a connection of its own. Listen and
+// Unlisten may be called while WaitForNotification is blocked.
+type Listener interface {
+	Listen(ctx context.Context, channel string) error
+	Unlisten(ctx context.Context, channel string) error // "*" for all channels
+	WaitForNotification(ctx context.Context) (Notification, error)
+	Close() error
+}
+
+// Notifier is implemented by connections whose server delivers
+// notifications, such as PostgreSQL's LISTEN/NOTIFY
+type Notifier interface {
+	OpenListener(ctx context.Context) (Listener, error)
+	Notify(ctx context.Context, channel, payload string) error
+}
@@ -1,6 +1,9 @@
 package db
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 var drivers = make(map[string]Driver)
 
@@ -18,11 +21,12 @@ func GetDriver(name string) (Driver, error) {
 	return driver, nil
 }
 
-// ListDrivers returns all registered driver names
+// ListDrivers returns all registered driver names, sorted
 func ListDrivers() []string {
 	names := make([]string, 0, len(drivers))
 	for name := range drivers {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
@@ -0,0 +1,36 @@
+package db
+
+import "fmt"
+
+// CollectRows reads up to limit rows from it, or every row when limit <= 0.
+// HasMore is set if the iterator had rows left over.
+func CollectRows(it RowIterator, limit int) (QueryResult, error) {
+	columns := it.Columns()
+	var rows [][]interface{}
+	hasMore := false
+
+	for it.Next() {
+		if limit > 0 && len(rows) == limit {
+			hasMore = true
+			break
+		}
+
+		values, err := it.Values()
+		if err != nil {
+			return QueryResult{}, fmt.Errorf("failed to scan row: %w", err)
+		}
+		rows = append(rows, values)
+	}
+
+	if err := it.Err(); err != nil {
+		return QueryResult{}, fmt.Errorf("rows error: %w", err)
+	}
+
+	return QueryResult{
+		Columns:    columns,
+		Rows:       rows,
+		RowCount:   len(rows),
+		HasMore:    hasMore,
+		ResultSets: []QueryResultSet{}, // Single result set for now
+	}, nil
+}
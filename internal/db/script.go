@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// ScriptError reports which statement of a script failed
+type ScriptError struct {
+	Index int // zero-based position of the statement
+	Total int // number of statements in the script
+	SQL   string
+	Err   error
+}
+
+func (e *ScriptError) Error() string {
+	if e.Total == 1 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("statement %d of %d failed: %v", e.Index+1, e.Total, e.Err)
+}
+
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
+
+// ScriptOptions controls how RunScript executes statements
+type ScriptOptions struct {
+	Limit           int  // rows returned per query
+	ContinueOnError bool // keep running after a statement fails
+	ManualCommit    bool // a statement outside a transaction begins one first
+}
+
+// RunScript executes statements in order on a session, returning one result
+// set per statement run. Unless opts.ContinueOnError is set, execution stops
+// at the first failing statement. The returned error is the first failure,
+// as a *ScriptError.
+func RunScript(ctx context.Context, session Session, statements []string, opts ScriptOptions) ([]QueryResultSet, error) {
+	var sets []QueryResultSet
+	var firstErr error
+
+	// Notices of statements run since the last script, like explains and
+	// exports, belong to none of this one's
+	TakeNotices(session)
+
+	for i, stmt := range statements {
+		var set QueryResultSet
+		if err := beginImplicitTx(ctx, session, stmt, opts.ManualCommit); err != nil {
+			set = QueryResultSet{SQL: stmt, Err: err}
+		} else {
+			set = RunStatement(ctx, session, stmt, opts.Limit)
+		}
+		set.TxStatus = session.TxStatus()
+		sets = append(sets, set)
+
+		if set.Err == nil {
+			continue
+		}
+		if firstErr == nil {
+			firstErr = &ScriptError{Index: i, Total: len(statements), SQL: stmt, Err: set.Err}
+		}
+		if !opts.ContinueOnError || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return sets, firstErr
+}
+
+// beginImplicitTx starts a transaction before stmt in manual commit mode if
+// none is open, like psql with AUTOCOMMIT off
+func beginImplicitTx(ctx context.Context, session Session, stmt string, manualCommit bool) error {
+	if !manualCommit || session.TxStatus() != TxIdle || IsTxControl(stmt) {
+		return nil
+	}
+
+	if _, err := session.Exec(ctx, "BEGIN"); err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return nil
+}
+
+// RunStatement executes a single statement through Query or Exec, depending
+// on whether it returns rows. Failures are recorded in the result set, and
+// so are the server's notices if conn collects them.
+func RunStatement(ctx context.Context, conn Executor, sql string, limit int) QueryResultSet {
+	set := QueryResultSet{SQL: sql}
+
+	if !ReturnsRows(sql) {
+		result, err := conn.Exec(ctx, sql)
+		set.CommandTag = result.CommandTag
+		set.RowsAffected = result.RowsAffected
+		set.Err = err
+	} else {
+		result, err := conn.Query(ctx, sql, limit, 0)
+		set.Columns = result.Columns
+		set.Rows = result.Rows
+		set.HasMore = result.HasMore
+		set.Err = err
+	}
+
+	set.Notices = TakeNotices(conn)
+	return set
+}
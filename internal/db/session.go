@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// TxStatus is the transaction state of a session
+type TxStatus int
+
+const (
+	TxIdle   TxStatus = iota // not in a transaction
+	TxActive                 // inside a transaction
+	TxFailed                 // transaction aborted by an error, awaiting ROLLBACK
+)
+
+// Executor runs statements. Connection runs them on any pooled connection,
+// Session on the one it holds.
+type Executor interface {
+	Query(ctx context.Context, sql string, limit int, offset int) (QueryResult, error)
+	QueryRows(ctx context.Context, sql string) (RowIterator, error)
+	Exec(ctx context.Context, sql string) (ExecResult, error)
+}
+
+// Session runs statements on a single server connection, so transaction
+// state and session settings carry over from one run to the next
+type Session interface {
+	Executor
+	TxStatus() TxStatus
+	Close() error
+}
+
+// IsTxControl reports whether a statement starts or ends a transaction
+func IsTxControl(sql string) bool {
+	switch FirstKeyword(sql) {
+	case "BEGIN", "START", "COMMIT", "END", "ROLLBACK", "ABORT", "SAVEPOINT", "RELEASE":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSessionSetting reports whether a statement changes a setting for the
+// rest of the session, such as SET search_path or USE, so it has to be run
+// again on a new connection. SET LOCAL and SET TRANSACTION end with the
+// transaction.
+func IsSessionSetting(sql string) bool {
+	switch FirstKeyword(sql) {
+	case "SET":
+		return !hasWord(sql, "LOCAL") && !hasWord(sql, "TRANSACTION")
+	case "RESET", "USE":
+		return true
+	default:
+		return false
+	}
+}
+
+// SettingName returns the lower-cased name of the setting a session setting
+// statement changes, so a later statement for the same setting can replace
+// it. USE is named "use", and RESET ALL "all".
+func SettingName(sql string) string {
+	keyword := FirstKeyword(sql)
+	if keyword == "USE" {
+		return "use"
+	}
+
+	rest := skipLeading(sql)[len(keyword):]
+	name := settingToken(rest)
+	if strings.EqualFold(name, "SESSION") {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)[len(name):]
+		name = settingToken(rest)
+	}
+
+	name = strings.ToLower(name)
+	name = strings.TrimPrefix(name, "@@session.")
+	name = strings.TrimPrefix(name, "@@")
+	// SET TIME ZONE is SET timezone
+	if name == "time" {
+		return "timezone"
+	}
+	return name
+}
+
+// settingToken returns the first token of s, up to a space, = or comma
+func settingToken(s string) string {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '=' || r == ',' || r == ';'
+	})
+	if end < 0 {
+		end = len(s)
+	}
+	return s[:end]
+}
+
+// TrackTxStatus returns the transaction state after sql ran successfully,
+// for drivers whose server doesn't report it
+func TrackTxStatus(status TxStatus, sql string) TxStatus {
+	switch FirstKeyword(sql) {
+	case "BEGIN":
+		return TxActive
+	case "START":
+		if hasWord(sql, "TRANSACTION") {
+			return TxActive
+		}
+		return status
+	case "COMMIT", "END":
+		return TxIdle
+	case "ROLLBACK":
+		// ROLLBACK TO SAVEPOINT keeps the transaction open
+		if hasWord(sql, "TO") {
+			return status
+		}
+		return TxIdle
+	default:
+		return status
+	}
+}
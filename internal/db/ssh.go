@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSHConfig holds the jump host a connection is tunneled through
+type SSHConfig struct {
+	Host           string // host or host:port of the SSH server, "" for no tunnel
+	User           string
+	KeyFile        string // private key, "" to authenticate with the SSH agent only
+	KnownHostsFile string // "" for ~/.ssh/known_hosts
+}
+
+// Enabled reports whether connections should go through the tunnel
+func (c SSHConfig) Enabled() bool {
+	return c.Host != ""
+}
+
+// Tunnel is an SSH connection that database connections are dialed through
+type Tunnel struct {
+	client *ssh.Client
+}
+
+// OpenTunnel connects and authenticates to the SSH server in config,
+// checking its host key against known_hosts
+func OpenTunnel(ctx context.Context, config SSHConfig) (*Tunnel, error) {
+	auth, closeAgent, err := sshAuthMethods(config)
+	if err != nil {
+		return nil, err
+	}
+	defer closeAgent()
+
+	hostKeyCallback, err := knownHostsCallback(config.KnownHostsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := config.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+
+	var dialer net.Dialer
+	netConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach SSH server: %w", err)
+	}
+
+	// The handshake has no context of its own
+	if deadline, ok := ctx.Deadline(); ok {
+		netConn.SetDeadline(deadline)
+	}
+
+	username := config.User
+	if username == "" {
+		if current, err := user.Current(); err == nil {
+			username = current.Username
+		}
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, addr, &ssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+	})
+	if err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("SSH connection failed: %w", explainHostKeyError(err, addr))
+	}
+	netConn.SetDeadline(time.Time{})
+
+	return &Tunnel{client: ssh.NewClient(sshConn, chans, reqs)}, nil
+}
+
+// Dial opens a connection to addr as seen from the SSH server
+func (t *Tunnel) Dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := t.client.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect through SSH tunnel: %w", err)
+	}
+	return conn, nil
+}
+
+// Close shuts the tunnel and every connection dialed through it. A nil
+// tunnel is a no-op, so connections without one can close it too.
+func (t *Tunnel) Close() error {
+	if t == nil {
+		return nil
+	}
+	return t.client.Close()
+}
+
+// sshAuthMethods returns public key auth with the key file, if set, and
+// the keys held by the SSH agent, if one is running. closeAgent must be
+// called once authentication is done.
+func sshAuthMethods(config SSHConfig) (auth []ssh.AuthMethod, closeAgent func(), err error) {
+	var signers []ssh.Signer
+	closeAgent = func() {}
+
+	if config.KeyFile != "" {
+		pem, err := os.ReadFile(expandHome(config.KeyFile))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read SSH key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(pem)
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) {
+			return nil, nil, fmt.Errorf("SSH key %s is encrypted; add it to ssh-agent and leave the key file empty", config.KeyFile)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse SSH key: %w", err)
+		}
+		signers = append(signers, signer)
+	}
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			closeAgent = func() { conn.Close() }
+			if agentSigners, err := agent.NewClient(conn).Signers(); err == nil {
+				signers = append(signers, agentSigners...)
+			}
+		}
+	}
+
+	if len(signers) == 0 {
+		closeAgent()
+		return nil, nil, fmt.Errorf("no SSH key: set a key file or start ssh-agent")
+	}
+	return []ssh.AuthMethod{ssh.PublicKeys(signers...)}, closeAgent, nil
+}
+
+// knownHostsCallback checks host keys against path, or ~/.ssh/known_hosts
+func knownHostsCallback(path string) (ssh.HostKeyCallback, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find known_hosts: %w", err)
+		}
+		path = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	callback, err := knownhosts.New(expandHome(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known_hosts: %w", err)
+	}
+	return callback, nil
+}
+
+// explainHostKeyError says what to do about a host key known_hosts rejected
+func explainHostKeyError(err error, addr string) error {
+	var keyErr *knownhosts.KeyError
+	if !errors.As(err, &keyErr) {
+		return err
+	}
+	if len(keyErr.Want) == 0 {
+		return fmt.Errorf("host key of %s is not in known_hosts; connect once with ssh to verify and add it", addr)
+	}
+	return fmt.Errorf("host key of %s does not match known_hosts, it may have been replaced or the connection intercepted", addr)
+}
+
+// expandHome replaces a leading ~/ with the user's home directory
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
@@ -0,0 +1,293 @@
+package db
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// newSigner generates an ed25519 key
+func newSigner(t *testing.T) (ssh.Signer, ed25519.PrivateKey) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+	return signer, key
+}
+
+// startSSHServer runs an SSH server on a free local port that accepts
+// clientKey and forwards direct-tcpip channels, and returns its address and
+// host key
+func startSSHServer(t *testing.T, clientKey ssh.PublicKey) (string, ssh.Signer) {
+	t.Helper()
+
+	hostKey, _ := newSigner(t)
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(clientKey.Marshal()) {
+				return nil, fmt.Errorf("unknown public key")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, config)
+		}
+	}()
+
+	return listener.Addr().String(), hostKey
+}
+
+// serveSSH forwards the direct-tcpip channels of an SSH connection
+func serveSSH(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChan := range chans {
+		if newChan.ChannelType() != "direct-tcpip" {
+			newChan.Reject(ssh.UnknownChannelType, "only direct-tcpip is supported")
+			continue
+		}
+
+		var target struct {
+			Host     string
+			Port     uint32
+			OrigHost string
+			OrigPort uint32
+		}
+		if err := ssh.Unmarshal(newChan.ExtraData(), &target); err != nil {
+			newChan.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		dest, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+		if err != nil {
+			newChan.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, chanReqs, err := newChan.Accept()
+		if err != nil {
+			dest.Close()
+			continue
+		}
+		go ssh.DiscardRequests(chanReqs)
+
+		go func() {
+			io.Copy(channel, dest)
+			channel.Close()
+		}()
+		go func() {
+			io.Copy(dest, channel)
+			dest.Close()
+		}()
+	}
+}
+
+// startEchoServer runs a TCP server that echoes back what it reads
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// writeKeyFile writes key as an OpenSSH private key file
+func writeKeyFile(t *testing.T, key ed25519.PrivateKey) string {
+	t.Helper()
+
+	block, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	return path
+}
+
+// writeKnownHosts writes a known_hosts file with key for addr
+func writeKnownHosts(t *testing.T, addr string, key ssh.PublicKey) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(addr)}, key) + "\n"
+	if err := os.WriteFile(path, []byte(line), 0o600); err != nil {
+		t.Fatalf("failed to write known_hosts: %v", err)
+	}
+	return path
+}
+
+// sshTestConfig starts a server and returns a config that authenticates to
+// it with a key file and trusts its host key
+func sshTestConfig(t *testing.T) SSHConfig {
+	t.Helper()
+
+	// Only the key file may be offered, not a running agent's keys
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	clientKey, key := newSigner(t)
+	addr, hostKey := startSSHServer(t, clientKey.PublicKey())
+
+	return SSHConfig{
+		Host:           addr,
+		User:           "tester",
+		KeyFile:        writeKeyFile(t, key),
+		KnownHostsFile: writeKnownHosts(t, addr, hostKey.PublicKey()),
+	}
+}
+
+// openTestTunnel opens a tunnel with config, giving up after 5s
+func openTestTunnel(t *testing.T, config SSHConfig) (*Tunnel, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return OpenTunnel(ctx, config)
+}
+
+func TestTunnelForwards(t *testing.T) {
+	tunnel, err := openTestTunnel(t, sshTestConfig(t))
+	if err != nil {
+		t.Fatalf("OpenTunnel() error = %v", err)
+	}
+	defer tunnel.Close()
+
+	conn, err := tunnel.Dial(context.Background(), "tcp", startEchoServer(t))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	reply := make([]byte, 4)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if string(reply) != "ping" {
+		t.Errorf("echoed %q, want %q", reply, "ping")
+	}
+}
+
+func TestTunnelRejectsUnauthorizedKey(t *testing.T) {
+	config := sshTestConfig(t)
+	_, otherKey := newSigner(t)
+	config.KeyFile = writeKeyFile(t, otherKey)
+
+	if _, err := openTestTunnel(t, config); err == nil {
+		t.Fatal("OpenTunnel() succeeded with a key the server doesn't accept")
+	}
+}
+
+func TestTunnelRejectsUnknownHost(t *testing.T) {
+	config := sshTestConfig(t)
+	otherHost, _ := newSigner(t)
+	config.KnownHostsFile = writeKnownHosts(t, "127.0.0.1:1", otherHost.PublicKey())
+
+	_, err := openTestTunnel(t, config)
+	if err == nil || !strings.Contains(err.Error(), "is not in known_hosts") {
+		t.Errorf("OpenTunnel() error = %v, want host key not in known_hosts", err)
+	}
+}
+
+func TestTunnelRejectsMismatchedHostKey(t *testing.T) {
+	config := sshTestConfig(t)
+	otherHost, _ := newSigner(t)
+	config.KnownHostsFile = writeKnownHosts(t, config.Host, otherHost.PublicKey())
+
+	_, err := openTestTunnel(t, config)
+	if err == nil || !strings.Contains(err.Error(), "does not match known_hosts") {
+		t.Errorf("OpenTunnel() error = %v, want host key mismatch", err)
+	}
+}
+
+func TestTunnelClose(t *testing.T) {
+	tunnel, err := openTestTunnel(t, sshTestConfig(t))
+	if err != nil {
+		t.Fatalf("OpenTunnel() error = %v", err)
+	}
+
+	echoAddr := startEchoServer(t)
+	conn, err := tunnel.Dial(context.Background(), "tcp", echoAddr)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+
+	if err := tunnel.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Connections dialed through the tunnel go down with it
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Error("Read() on a forwarded connection succeeded after Close")
+	} else if os.IsTimeout(err) {
+		t.Error("forwarded connection still open after Close")
+	}
+
+	if _, err := tunnel.Dial(context.Background(), "tcp", echoAddr); err == nil {
+		t.Error("Dial() succeeded after Close")
+	}
+}
+
+func TestTunnelCloseNil(t *testing.T) {
+	var tunnel *Tunnel
+	if err := tunnel.Close(); err != nil {
+		t.Errorf("Close() on nil tunnel error = %v", err)
+	}
+}
@@ -0,0 +1,298 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// FirstKeyword returns the upper-cased leading keyword of a statement,
+// skipping whitespace, comments and opening parentheses
+func FirstKeyword(sql string) string {
+	rest := skipLeading(sql)
+
+	end := strings.IndexFunc(rest, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '_'
+	})
+	if end < 0 {
+		end = len(rest)
+	}
+
+	return strings.ToUpper(rest[:end])
+}
+
+// TrimStatement strips surrounding whitespace and trailing semicolons
+func TrimStatement(sql string) string {
+	return strings.TrimRightFunc(strings.TrimSpace(sql), func(r rune) bool {
+		return r == ';' || unicode.IsSpace(r)
+	})
+}
+
+// skipLeading drops whitespace, comments and parentheses before the first token
+func skipLeading(sql string) string {
+	for {
+		trimmed := strings.TrimLeftFunc(sql, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '('
+		})
+
+		switch {
+		case strings.HasPrefix(trimmed, "--"):
+			end := strings.IndexByte(trimmed, '\n')
+			if end < 0 {
+				return ""
+			}
+			sql = trimmed[end+1:]
+		case strings.HasPrefix(trimmed, "/*"):
+			sql = skipBlockComment(trimmed)
+		default:
+			return trimmed
+		}
+	}
+}
+
+// skipBlockComment returns the text after a (possibly nested) /* */ comment
+func skipBlockComment(sql string) string {
+	depth := 0
+	for i := 0; i < len(sql)-1; i++ {
+		switch {
+		case sql[i] == '/' && sql[i+1] == '*':
+			depth++
+			i++
+		case sql[i] == '*' && sql[i+1] == '/':
+			depth--
+			i++
+			if depth == 0 {
+				return sql[i+1:]
+			}
+		}
+	}
+	return ""
+}
+
+// SplitStatements splits a script into its statements at semicolons outside
+// literals, comments and parentheses. Empty statements are dropped.
+func SplitStatements(sql string) []string {
+	var statements []string
+	start, depth := 0, 0
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '(':
+			depth++
+			i++
+		case c == ')':
+			depth--
+			i++
+		case c == ';' && depth <= 0:
+			statements = appendStatement(statements, sql[start:i])
+			i++
+			start, depth = i, 0
+		case isWordStart(c):
+			i = skipWord(sql, i)
+		default:
+			i = skipToken(sql, i)
+		}
+	}
+
+	return appendStatement(statements, sql[start:])
+}
+
+// appendStatement appends stmt unless it's only whitespace and comments
+func appendStatement(statements []string, stmt string) []string {
+	stmt = strings.TrimSpace(stmt)
+	if len(words(stmt)) == 0 {
+		return statements
+	}
+	return append(statements, stmt)
+}
+
+// ReturnsRows reports whether a statement produces a result set, and so
+// should run through Connection.Query rather than Connection.Exec
+func ReturnsRows(sql string) bool {
+	switch FirstKeyword(sql) {
+	case "SELECT", "VALUES", "TABLE", "SHOW", "EXPLAIN", "PRAGMA", "DESCRIBE", "DESC", "FETCH", "CALL", "EXECUTE":
+		return true
+	case "INSERT", "UPDATE", "DELETE", "MERGE", "REPLACE":
+		return hasWord(sql, "RETURNING")
+	case "WITH":
+		// Decided by the statement following the CTE list
+		switch mainKeyword(sql) {
+		case "SELECT", "VALUES", "TABLE", "":
+			return true
+		default:
+			return hasWord(sql, "RETURNING")
+		}
+	default:
+		return false
+	}
+}
+
+// CommandTag builds a Postgres-style command tag such as "UPDATE 42" or
+// "CREATE TABLE" for drivers that only report the number of rows affected
+func CommandTag(sql string, rowsAffected int64) string {
+	ws := words(sql)
+	if len(ws) == 0 {
+		return ""
+	}
+
+	keyword := ws[0].text
+	if main := mainKeyword(sql); keyword == "WITH" && main != "" {
+		keyword = main
+	}
+
+	switch keyword {
+	case "INSERT", "UPDATE", "DELETE", "MERGE", "REPLACE":
+		return fmt.Sprintf("%s %d", keyword, rowsAffected)
+	case "CREATE", "DROP", "ALTER":
+		// Skip modifiers to find the object type
+		for _, w := range ws[1:] {
+			switch w.text {
+			case "OR", "REPLACE", "TEMP", "TEMPORARY", "UNIQUE", "UNLOGGED", "GLOBAL", "LOCAL":
+				continue
+			}
+			return keyword + " " + w.text
+		}
+	}
+	return keyword
+}
+
+// word is a bare keyword or identifier and the parenthesis depth it was found at
+type word struct {
+	text  string
+	depth int
+}
+
+// words returns the upper-cased bare words of sql, skipping comments,
+// string literals and quoted identifiers
+func words(sql string) []word {
+	var result []word
+	depth := 0
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '(':
+			depth++
+			i++
+		case c == ')':
+			depth--
+			i++
+		case isWordStart(c):
+			start := i
+			i = skipWord(sql, i)
+			if sql[i-1] != '\'' {
+				result = append(result, word{text: strings.ToUpper(sql[start:i]), depth: depth})
+			}
+		default:
+			i = skipToken(sql, i)
+		}
+	}
+
+	return result
+}
+
+// skipWord returns the index after the word starting at i, or after the
+// string literal if the word is the E prefix of an E'...' string
+func skipWord(sql string, i int) int {
+	start := i
+	for i < len(sql) && isWordPart(sql[i]) {
+		i++
+	}
+	// E'...' strings take backslash escapes
+	if i < len(sql) && sql[i] == '\'' && i-start == 1 && (sql[start] == 'E' || sql[start] == 'e') {
+		return skipQuoted(sql, i, true)
+	}
+	return i
+}
+
+// skipToken returns the index after the literal, comment or single
+// character starting at i
+func skipToken(sql string, i int) int {
+	switch {
+	case sql[i] == '\'':
+		return skipQuoted(sql, i, false)
+	case sql[i] == '"' || sql[i] == '`':
+		return skipQuoted(sql, i, false)
+	case strings.HasPrefix(sql[i:], "--"):
+		if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+			return i + end + 1
+		}
+		return len(sql)
+	case strings.HasPrefix(sql[i:], "/*"):
+		return len(sql) - len(skipBlockComment(sql[i:]))
+	case sql[i] == '$':
+		if tag, ok := dollarTag(sql[i:]); ok {
+			if end := strings.Index(sql[i+len(tag):], tag); end >= 0 {
+				return i + len(tag) + end + len(tag)
+			}
+			return len(sql)
+		}
+	}
+	return i + 1
+}
+
+// skipQuoted returns the index after the quoted text starting at i. A doubled
+// quote character is an escaped quote.
+func skipQuoted(sql string, i int, backslashEscapes bool) int {
+	quote := sql[i]
+	for i++; i < len(sql); i++ {
+		switch {
+		case backslashEscapes && sql[i] == '\\':
+			i++
+		case sql[i] == quote:
+			if i+1 < len(sql) && sql[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(sql)
+}
+
+// dollarTag returns the $tag$ opening a dollar-quoted string
+func dollarTag(s string) (string, bool) {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '$' {
+			return s[:i+1], true
+		}
+		if !isWordPart(s[i]) || (i == 1 && s[i] >= '0' && s[i] <= '9') {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// mainKeyword returns the statement keyword that follows a WITH clause
+func mainKeyword(sql string) string {
+	for _, w := range words(sql) {
+		if w.depth != 0 {
+			continue
+		}
+		switch w.text {
+		case "SELECT", "INSERT", "UPDATE", "DELETE", "MERGE", "VALUES", "TABLE":
+			return w.text
+		}
+	}
+	return ""
+}
+
+// hasWord reports whether keyword appears at the top level, outside
+// parentheses, literals and comments
+func hasWord(sql, keyword string) bool {
+	for _, w := range words(sql) {
+		if w.depth == 0 && w.text == keyword {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isWordPart(c byte) bool {
+	return isWordStart(c) || (c >= '0' && c <= '9') || c == '$'
+}
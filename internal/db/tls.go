@@ -0,0 +1,66 @@
+package db
+
+import (
+	"crypto/x509"
+	"errors"
+	"strings"
+)
+
+// TLSModes are the supported TLS modes, named as in libpq's sslmode
+var TLSModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// TLSConfig holds a connection's TLS settings
+type TLSConfig struct {
+	Mode       string // one of TLSModes, "" for the driver's default
+	CAFile     string // PEM bundle of CAs to verify the server with
+	CertFile   string // client certificate, PEM
+	KeyFile    string // client certificate's private key, PEM
+	ServerName string // name the server certificate must match, instead of the host
+}
+
+// TLSError is a connection failure caused by TLS, with a hint at which
+// setting to change
+type TLSError struct {
+	Hint string
+	Err  error
+}
+
+func (e *TLSError) Error() string {
+	return e.Hint + ": " + e.Err.Error()
+}
+
+func (e *TLSError) Unwrap() error {
+	return e.Err
+}
+
+// WrapTLSError explains err as a *TLSError if it comes from the TLS
+// handshake, and returns it unchanged otherwise
+func WrapTLSError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+
+	var hint string
+	switch {
+	case errors.As(err, &unknownAuthority):
+		hint = "server certificate is not signed by a trusted CA; set the CA file to the server's CA bundle"
+	case errors.As(err, &hostname):
+		hint = "server certificate doesn't match the host; set the TLS server name to a name it lists"
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		hint = "server certificate has expired or is not valid yet"
+	case errors.As(err, &invalid):
+		hint = "server certificate is invalid"
+	case strings.Contains(err.Error(), "refused TLS") || strings.Contains(err.Error(), "does not support TLS"):
+		hint = "server doesn't accept TLS; use SSL mode prefer or disable"
+	case strings.Contains(err.Error(), "tls:") || strings.Contains(err.Error(), "x509:"):
+		hint = "TLS handshake failed"
+	default:
+		return err
+	}
+
+	return &TLSError{Hint: hint, Err: err}
+}
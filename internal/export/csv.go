@@ -0,0 +1,49 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/imran-vz/gosqlit/internal/format"
+)
+
+// WriteCSV streams every row of it to w as CSV with a header row. Rows are
+// written as they are read, so memory use doesn't grow with the result size.
+func WriteCSV(w io.Writer, it db.RowIterator) (int, error) {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(db.ColumnNames(it.Columns())); err != nil {
+		return 0, fmt.Errorf("failed to write header: %w", err)
+	}
+
+	// Cells are formatted as on screen, except NULL is left empty
+	opts := format.Defaults()
+	opts.Null = ""
+
+	columns := it.Columns()
+	count := 0
+	record := make([]string, len(columns))
+	for it.Next() {
+		values, err := it.Values()
+		if err != nil {
+			return count, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		for i, v := range values {
+			record[i] = opts.Value(v, columns[i])
+		}
+		if err := writer.Write(record); err != nil {
+			return count, fmt.Errorf("failed to write row: %w", err)
+		}
+		count++
+	}
+
+	if err := it.Err(); err != nil {
+		return count, fmt.Errorf("rows error: %w", err)
+	}
+
+	writer.Flush()
+	return count, writer.Error()
+}
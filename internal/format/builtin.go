@@ -0,0 +1,157 @@
+package format
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+func init() {
+	RegisterKind(db.KindJSON, formatJSON)
+	RegisterType([]byte(nil), formatBinary)
+	RegisterType(time.Time{}, formatTime)
+	RegisterType(float64(0), formatFloat)
+	RegisterType(float32(0), formatFloat)
+	RegisterType([16]byte{}, formatUUID)
+	RegisterType([]any(nil), formatArray)
+	RegisterType(map[string]any(nil), formatJSON)
+}
+
+// formatJSON writes JSON without insignificant whitespace
+func formatJSON(v any, col db.Column, opts Options) (string, bool) {
+	var text []byte
+	switch v := v.(type) {
+	case string:
+		text = []byte(v)
+	case []byte:
+		text = v
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, text); err != nil {
+		return string(text), true
+	}
+	return buf.String(), true
+}
+
+// formatBinary writes bytes in hex or escape form. Text columns some
+// drivers return as bytes are written as text.
+func formatBinary(v any, col db.Column, opts Options) (string, bool) {
+	b := v.([]byte)
+	if col.Kind != db.KindBinary && col.Kind != db.KindUnknown && utf8.Valid(b) {
+		return string(b), true
+	}
+
+	if opts.Binary == BinaryEscape {
+		var s strings.Builder
+		for _, c := range b {
+			switch {
+			case c == '\\':
+				s.WriteString(`\\`)
+			case c >= 0x20 && c < 0x7f:
+				s.WriteByte(c)
+			default:
+				fmt.Fprintf(&s, `\%03o`, c)
+			}
+		}
+		return s.String(), true
+	}
+	return `\x` + hex.EncodeToString(b), true
+}
+
+// formatTime writes dates as dates and timestamps in opts.TimeLayout, or
+// ISO 8601 with microseconds, the way Postgres prints them
+func formatTime(v any, col db.Column, opts Options) (string, bool) {
+	t := v.(time.Time)
+	typeName := strings.ToLower(col.TypeName)
+	if typeName == "date" {
+		return t.Format(time.DateOnly), true
+	}
+
+	zoned := typeName == "timestamptz" || typeName == "timestamp with time zone"
+	if zoned && opts.Location != nil {
+		t = t.In(opts.Location)
+	}
+
+	layout := opts.TimeLayout
+	if layout == "" {
+		layout = "2006-01-02 15:04:05.999999"
+		if zoned {
+			layout += "-07:00"
+		}
+	}
+	return t.Format(layout), true
+}
+
+// formatFloat writes floats at their shortest exact precision, switching to
+// exponent form only for very large or small magnitudes
+func formatFloat(v any, col db.Column, opts Options) (string, bool) {
+	f, bits := 0.0, 64
+	switch v := v.(type) {
+	case float64:
+		f = v
+	case float32:
+		f, bits = float64(v), 32
+	}
+
+	if abs := math.Abs(f); abs >= 1e15 || (abs != 0 && abs < 1e-4) {
+		return strconv.FormatFloat(f, 'g', -1, bits), true
+	}
+	return strconv.FormatFloat(f, 'f', -1, bits), true
+}
+
+// formatUUID writes a 16 byte UUID in its canonical form
+func formatUUID(v any, col db.Column, opts Options) (string, bool) {
+	u := v.([16]byte)
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]), true
+}
+
+// formatArray writes an array as a Postgres array literal, e.g. {1,2,NULL}
+func formatArray(v any, col db.Column, opts Options) (string, bool) {
+	elemType := strings.TrimSuffix(strings.TrimPrefix(col.TypeName, "_"), "[]")
+	elemCol := db.Column{TypeName: elemType, Kind: db.KindForTypeName(elemType)}
+
+	var s strings.Builder
+	s.WriteByte('{')
+	for i, elem := range v.([]any) {
+		if i > 0 {
+			s.WriteByte(',')
+		}
+		switch elem := elem.(type) {
+		case nil:
+			s.WriteString("NULL")
+		case []any:
+			nested, _ := formatArray(elem, col, opts)
+			s.WriteString(nested)
+		default:
+			s.WriteString(quoteArrayElement(opts.Value(elem, elemCol)))
+		}
+	}
+	s.WriteByte('}')
+	return s.String(), true
+}
+
+// quoteArrayElement double quotes an array element when Postgres would
+func quoteArrayElement(s string) string {
+	if s != "" && !strings.EqualFold(s, "NULL") && !strings.ContainsAny(s, "{},\"\\ \t\n\r") {
+		return s
+	}
+
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,95 @@
+package format
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// BinaryFormat selects how binary values are written
+type BinaryFormat int
+
+const (
+	BinaryHex    BinaryFormat = iota // \x0a1b, like Postgres' bytea_output = hex
+	BinaryEscape                     // printable bytes as-is, others as \ooo
+)
+
+// Options controls how values are rendered as text
+type Options struct {
+	Null       string         // text for NULL
+	Binary     BinaryFormat   // how binary values are written
+	Location   *time.Location // zone timestamps with a time zone are shown in; nil keeps the server's
+	TimeLayout string         // Go layout for timestamps; "" picks one matching the column type
+}
+
+// Func renders v from column col, reporting false if it doesn't handle v
+type Func func(v any, col db.Column, opts Options) (string, bool)
+
+var (
+	kindFuncs = make(map[db.ColumnKind]Func)
+	typeFuncs = make(map[reflect.Type]Func)
+	defaults  = Options{Null: "NULL"}
+)
+
+// RegisterKind registers a formatter tried first for columns of kind
+func RegisterKind(kind db.ColumnKind, fn Func) {
+	kindFuncs[kind] = fn
+}
+
+// RegisterType registers a formatter for values of the same Go type as sample
+func RegisterType(sample any, fn Func) {
+	typeFuncs[reflect.TypeOf(sample)] = fn
+}
+
+// SetDefaults sets the options used by Value
+func SetDefaults(opts Options) {
+	defaults = opts
+}
+
+// Defaults returns the options used by Value
+func Defaults() Options {
+	return defaults
+}
+
+// Value renders v from column col with the default options
+func Value(v any, col db.Column) string {
+	return defaults.Value(v, col)
+}
+
+// Value renders v from column col. Formatters registered for the column's
+// kind are tried before those for the value's type; values no formatter
+// handles fall back to their driver.Valuer text or fmt's default.
+func (o Options) Value(v any, col db.Column) string {
+	if v == nil {
+		return o.Null
+	}
+
+	if fn, ok := kindFuncs[col.Kind]; ok {
+		if s, ok := fn(v, col, o); ok {
+			return s
+		}
+	}
+	if fn, ok := typeFuncs[reflect.TypeOf(v)]; ok {
+		if s, ok := fn(v, col, o); ok {
+			return s
+		}
+	}
+
+	// Driver types such as pgtype.Numeric and pgtype.Interval give their
+	// exact text form this way
+	if valuer, ok := v.(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil {
+			switch value := value.(type) {
+			case nil:
+				return o.Null
+			case string:
+				return value
+			}
+		}
+	}
+
+	return fmt.Sprintf("%v", v)
+}
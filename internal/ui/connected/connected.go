@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/imran-vz/gosqlit/internal/db"
 	"github.com/imran-vz/gosqlit/internal/debug"
 )
 
@@ -18,20 +19,41 @@ const (
 	PaneResults
 )
 
+// ResultsMode is what the results pane shows
+type ResultsMode int
+
+const (
+	ResultsGrid      ResultsMode = iota // query results
+	ResultsInspector                    // structure of a table
+	ResultsPlan                         // query plan
+	ResultsSessions                     // server backends
+	ResultsLocks                        // who blocks whom
+	ResultsNotifications                // LISTEN/NOTIFY messages
+)
+
 // ConnectedView is main workspace view
 type ConnectedView struct {
-	ConnID       string
-	FocusedPane  PaneType
-	LeftWidth    int // percentage (0-100)
-	QueryRunning bool
-	CancelFunc   context.CancelFunc
-	DebugMode    bool // debug flag
+	ConnID          string
+	FocusedPane     PaneType
+	LeftWidth       int // percentage (0-100)
+	QueryRunning    bool
+	CancelFunc      context.CancelFunc
+	ContinueOnError bool // keep running a script after a statement fails
+	ManualCommit    bool // statements outside a transaction begin one
+	TxStatus        db.TxStatus
+	DebugMode       bool // debug flag
 
 	// Components
-	Browser   *SchemaBrowser
-	Editor    *QueryEditor
-	Results   *ResultsTable
-	StatusBar *StatusBar
+	Browser       *SchemaBrowser
+	Editor        *QueryEditor
+	Results       *ResultsTable
+	Inspector     *Inspector
+	Plan          *PlanView
+	Sessions      *SessionsView
+	Locks         *LocksView
+	Notifications *NotificationsView
+	StatusBar     *StatusBar
+	ResultsMode   ResultsMode // what the results pane shows
 
 	// Dimensions
 	width  int
@@ -48,10 +70,15 @@ func NewConnectedView(connID string, connInfo string) *ConnectedView {
 		LeftWidth:   25, // 25% for left panel
 		DebugMode:   debug.Enabled(),
 
-		Browser:   NewSchemaBrowser(),
-		Editor:    NewQueryEditor(),
-		Results:   NewResultsTable(),
-		StatusBar: NewStatusBar(connInfo),
+		Browser:       NewSchemaBrowser(),
+		Editor:        NewQueryEditor(),
+		Results:       NewResultsTable(),
+		Inspector:     NewInspector(),
+		Plan:          NewPlanView(),
+		Sessions:      NewSessionsView(),
+		Locks:         NewLocksView(),
+		Notifications: NewNotificationsView(),
+		StatusBar:     NewStatusBar(connInfo),
 	}
 }
 
@@ -85,6 +112,11 @@ func (cv *ConnectedView) Update(msg tea.Msg) (*ConnectedView, tea.Cmd) {
 			}
 			debug.Logf("Tab handled - new pane: %d", cv.FocusedPane)
 			return cv, nil
+		case "esc":
+			if cv.FocusedPane == PaneResults && cv.ResultsMode != ResultsGrid {
+				cv.ResultsMode = ResultsGrid
+				return cv, nil
+			}
 		}
 	}
 
@@ -97,6 +129,23 @@ func (cv *ConnectedView) Update(msg tea.Msg) (*ConnectedView, tea.Cmd) {
 		debug.Logf("Delegating to query editor (pane focused)")
 		cv.Editor, cmd = cv.Editor.Update(msg)
 	case PaneResults:
+		switch cv.ResultsMode {
+		case ResultsInspector:
+			cv.Inspector, cmd = cv.Inspector.Update(msg)
+			return cv, cmd
+		case ResultsPlan:
+			cv.Plan, cmd = cv.Plan.Update(msg)
+			return cv, cmd
+		case ResultsSessions:
+			cv.Sessions, cmd = cv.Sessions.Update(msg)
+			return cv, cmd
+		case ResultsLocks:
+			cv.Locks, cmd = cv.Locks.Update(msg)
+			return cv, cmd
+		case ResultsNotifications:
+			cv.Notifications, cmd = cv.Notifications.Update(msg)
+			return cv, cmd
+		}
 		debug.Logf("Delegating to results table")
 		cv.Results, cmd = cv.Results.Update(msg)
 	}
@@ -104,6 +153,42 @@ func (cv *ConnectedView) Update(msg tea.Msg) (*ConnectedView, tea.Cmd) {
 	return cv, cmd
 }
 
+// Inspect shows the inspector, focused, while schema.table is described
+func (cv *ConnectedView) Inspect(schema, table string) {
+	cv.Inspector.SetLoading(schema, table)
+	cv.ResultsMode = ResultsInspector
+	cv.FocusedPane = PaneResults
+}
+
+// Explain shows the plan view, focused, while the editor's statement is
+// explained
+func (cv *ConnectedView) Explain(analyze bool) {
+	cv.Plan.SetLoading(analyze)
+	cv.ResultsMode = ResultsPlan
+	cv.FocusedPane = PaneResults
+}
+
+// ShowSessions shows the sessions view, focused, and returns the generation
+// its refreshes belong to
+func (cv *ConnectedView) ShowSessions() int {
+	cv.ResultsMode = ResultsSessions
+	cv.FocusedPane = PaneResults
+	return cv.Sessions.Open()
+}
+
+// ShowLocks shows the locks view, focused, while lock waits are listed
+func (cv *ConnectedView) ShowLocks() {
+	cv.Locks.SetLoading()
+	cv.ResultsMode = ResultsLocks
+	cv.FocusedPane = PaneResults
+}
+
+// ShowNotifications shows the notifications view, focused
+func (cv *ConnectedView) ShowNotifications() {
+	cv.ResultsMode = ResultsNotifications
+	cv.FocusedPane = PaneResults
+}
+
 // View renders the view
 func (cv *ConnectedView) View(width, height int) string {
 	cv.width = width
@@ -139,6 +224,18 @@ func (cv *ConnectedView) View(width, height int) string {
 	browserView := cv.Browser.View()
 	editorView := cv.Editor.View()
 	resultsView := cv.Results.View()
+	switch cv.ResultsMode {
+	case ResultsInspector:
+		resultsView = cv.Inspector.View()
+	case ResultsPlan:
+		resultsView = cv.Plan.View()
+	case ResultsSessions:
+		resultsView = cv.Sessions.View()
+	case ResultsLocks:
+		resultsView = cv.Locks.View()
+	case ResultsNotifications:
+		resultsView = cv.Notifications.View()
+	}
 	statusView := cv.StatusBar.View()
 
 	// Apply focus styles with borders
@@ -232,5 +329,10 @@ func (cv *ConnectedView) updateDimensions() {
 	cv.Browser.SetDimensions(browserContentWidth, browserContentHeight)
 	cv.Editor.SetDimensions(editorContentWidth, editorContentHeight)
 	cv.Results.SetDimensions(resultsContentWidth, resultsContentHeight)
+	cv.Inspector.SetDimensions(resultsContentWidth, resultsContentHeight)
+	cv.Plan.SetDimensions(resultsContentWidth, resultsContentHeight)
+	cv.Sessions.SetDimensions(resultsContentWidth, resultsContentHeight)
+	cv.Locks.SetDimensions(resultsContentWidth, resultsContentHeight)
+	cv.Notifications.SetDimensions(resultsContentWidth, resultsContentHeight)
 	cv.StatusBar.SetWidth(cv.width)
 }
@@ -0,0 +1,232 @@
+package connected
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// Inspector describes a table's structure the way psql's \d+ does:
+// columns, then indexes, constraints, foreign keys and the comment
+type Inspector struct {
+	schema  string
+	table   string
+	loading bool
+	err     error
+	lines   []string // rendered description
+	scroll  int
+	width   int
+	height  int
+}
+
+// NewInspector creates an empty inspector
+func NewInspector() *Inspector {
+	return &Inspector{}
+}
+
+// Update handles messages
+func (in *Inspector) Update(msg tea.Msg) (*Inspector, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		maxScroll := max(len(in.lines)-in.visibleLines(), 0)
+		switch keyMsg.String() {
+		case "up", "k":
+			in.scroll--
+		case "down", "j":
+			in.scroll++
+		case "pageup":
+			in.scroll -= 10
+		case "pagedown":
+			in.scroll += 10
+		case "home":
+			in.scroll = 0
+		case "end":
+			in.scroll = maxScroll
+		}
+		in.scroll = min(max(in.scroll, 0), maxScroll)
+	}
+	return in, nil
+}
+
+// View renders the description
+func (in *Inspector) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("62")).
+		Bold(true).
+		PaddingLeft(1)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		PaddingLeft(1)
+
+	title := titleStyle.Render(fmt.Sprintf("Table %s.%s", in.schema, in.table)) +
+		dimStyle.Render("(Esc: back to results)")
+
+	switch {
+	case in.loading:
+		return title + "\n\n" + dimStyle.Render("Loading...")
+	case in.err != nil:
+		return title + "\n\n" + lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			PaddingLeft(1).
+			Width(max(in.width-2, 20)).
+			Render("Error: "+in.err.Error())
+	}
+
+	end := min(in.scroll+in.visibleLines(), len(in.lines))
+	visible := make([]string, 0, end-in.scroll)
+	for _, line := range in.lines[in.scroll:end] {
+		visible = append(visible, " "+truncateString(line, max(in.width-2, 10)))
+	}
+	return title + "\n\n" + strings.Join(visible, "\n")
+}
+
+// visibleLines is the number of description lines that fit
+func (in *Inspector) visibleLines() int {
+	return max(in.height-4, 1)
+}
+
+// SetLoading clears the inspector while schema.table is described
+func (in *Inspector) SetLoading(schema, table string) {
+	in.schema = schema
+	in.table = table
+	in.err = nil
+	in.lines = nil
+	in.scroll = 0
+	in.loading = true
+}
+
+// SetInfo shows the description of schema.table, unless another table has
+// been selected since
+func (in *Inspector) SetInfo(schema, table string, info db.TableInfo, err error) {
+	if schema != in.schema || table != in.table {
+		return
+	}
+	in.err = err
+	in.loading = false
+	in.lines = describeTable(info)
+}
+
+// SetDimensions sets width and height
+func (in *Inspector) SetDimensions(width, height int) {
+	in.width = width
+	in.height = height
+}
+
+// describeTable renders info as psql's \d+ lays it out
+func describeTable(info db.TableInfo) []string {
+	headers := []string{"Column", "Type", "Nullable", "Default", "Key", "Comment"}
+	rows := make([][]string, len(info.Columns))
+	for i, col := range info.Columns {
+		nullable := ""
+		if !col.Nullable {
+			nullable = "not null"
+		}
+		rows[i] = []string{col.Name, col.Type, nullable, columnDefault(col), col.Key, col.Comment}
+	}
+
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = runeWidth(header)
+		for _, row := range rows {
+			widths[i] = max(widths[i], runeWidth(row[i]))
+		}
+	}
+
+	joinRow := func(cells []string) string {
+		padded := make([]string, len(cells))
+		for i, cell := range cells {
+			padded[i] = padToWidth(cell, widths[i])
+		}
+		return strings.TrimRight(strings.Join(padded, " | "), " ")
+	}
+
+	separators := make([]string, len(widths))
+	for i, width := range widths {
+		separators[i] = strings.Repeat("─", width)
+	}
+
+	lines := []string{joinRow(headers), strings.Join(separators, "─┼─")}
+	for _, row := range rows {
+		lines = append(lines, joinRow(row))
+	}
+
+	section := func(heading string, entries []string) {
+		if len(entries) == 0 {
+			return
+		}
+		lines = append(lines, heading+":")
+		for _, entry := range entries {
+			lines = append(lines, "    "+entry)
+		}
+	}
+
+	// Unique constraints are listed with the index that enforces them
+	constraintNames := make(map[string]bool)
+	for _, unique := range info.Uniques {
+		constraintNames[unique.Name] = true
+	}
+	var indexes, uniques []string
+	for _, index := range info.Indexes {
+		kind := ""
+		switch {
+		case index.Primary:
+			kind = "PRIMARY KEY, "
+		case index.Unique && constraintNames[index.Name]:
+			kind = "UNIQUE CONSTRAINT, "
+			delete(constraintNames, index.Name)
+		case index.Unique:
+			kind = "UNIQUE, "
+		}
+		indexes = append(indexes, fmt.Sprintf("%q %s%s (%s)", index.Name, kind, index.Method, strings.Join(index.Columns, ", ")))
+	}
+	if len(info.Indexes) == 0 && info.PrimaryKey != nil {
+		indexes = append(indexes, fmt.Sprintf("%q PRIMARY KEY (%s)", info.PrimaryKey.Name, strings.Join(info.PrimaryKey.Columns, ", ")))
+	}
+	for _, unique := range info.Uniques {
+		if constraintNames[unique.Name] {
+			uniques = append(uniques, fmt.Sprintf("%q %s", unique.Name, unique.Definition))
+		}
+	}
+
+	var checks, foreignKeys []string
+	for _, check := range info.Checks {
+		checks = append(checks, fmt.Sprintf("%q %s", check.Name, check.Definition))
+	}
+	for _, fk := range info.ForeignKeys {
+		definition := fk.Definition
+		if definition == "" {
+			definition = fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s.%s(%s)",
+				strings.Join(fk.Columns, ", "), fk.RefSchema, fk.RefTable, strings.Join(fk.RefColumns, ", "))
+		}
+		foreignKeys = append(foreignKeys, fmt.Sprintf("%q %s", fk.Name, definition))
+	}
+
+	section("Indexes", indexes)
+	section("Unique constraints", uniques)
+	section("Check constraints", checks)
+	section("Foreign-key constraints", foreignKeys)
+	if info.Comment != "" {
+		lines = append(lines, "Comment: "+info.Comment)
+	}
+
+	// Multi-line expressions are shown on one line
+	flatten := strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ")
+	for i, line := range lines {
+		lines[i] = flatten.Replace(line)
+	}
+	return lines
+}
+
+// columnDefault is what psql shows in a column's Default: its default
+// expression, or how an identity or generated column gets its value
+func columnDefault(col db.ColumnInfo) string {
+	switch {
+	case col.Identity != "":
+		return "generated " + strings.ToLower(col.Identity) + " as identity"
+	case col.Generated != "":
+		return "generated always as (" + col.Generated + ") stored"
+	}
+	return col.Default
+}
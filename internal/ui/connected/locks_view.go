@@ -0,0 +1,211 @@
+package connected
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/imran-vz/gosqlit/pkg/treeview"
+)
+
+// LocksView shows who blocks whom as a tree: root blockers at the top, the
+// backends waiting on each below it
+type LocksView struct {
+	tree    *treeview.Tree
+	waits   int // backends taking part
+	loading bool
+	err     error
+	width   int
+	height  int
+}
+
+// NewLocksView creates an empty locks view
+func NewLocksView() *LocksView {
+	return &LocksView{
+		tree: treeview.NewTree(nil),
+	}
+}
+
+// Update handles messages
+func (lv *LocksView) Update(msg tea.Msg) (*LocksView, tea.Cmd) {
+	newTree, cmd := lv.tree.Update(msg)
+	lv.tree = newTree
+	return lv, cmd
+}
+
+// View renders the blocking tree
+func (lv *LocksView) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("62")).
+		Bold(true).
+		PaddingLeft(1)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		PaddingLeft(1)
+
+	title := titleStyle.Render("Locks") +
+		dimStyle.Render("c: cancel blocker's query · x: terminate blocker · r: refresh · Esc: back")
+
+	switch {
+	case lv.loading:
+		return title + "\n\n" + dimStyle.Render("Loading...")
+	case lv.err != nil:
+		return title + "\n\n" + lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			PaddingLeft(1).
+			Width(max(lv.width-2, 20)).
+			Render("Error: "+lv.err.Error())
+	case lv.waits == 0:
+		return title + "\n\n" + dimStyle.Render("No backend is waiting for a lock")
+	}
+
+	return title + "\n\n" + lipgloss.NewStyle().
+		PaddingLeft(1).
+		Render(lv.tree.View(max(lv.width-2, 10), max(lv.height-4, 1)))
+}
+
+// SetLoading clears the view while lock waits are listed
+func (lv *LocksView) SetLoading() {
+	lv.loading = true
+	lv.err = nil
+}
+
+// SetLockWaits shows the blocking tree of waits
+func (lv *LocksView) SetLockWaits(waits []db.LockWait, err error) {
+	lv.loading = false
+	lv.err = err
+	if err != nil {
+		return
+	}
+	lv.waits = len(waits)
+	lv.tree.SetRoot(&treeview.Node{
+		ID:       "root",
+		Label:    fmt.Sprintf("%d backends involved", len(waits)),
+		Expanded: true,
+		Children: blockingTree(waits, time.Now()),
+	})
+}
+
+// SelectedBlocker returns the root blocker under the cursor
+func (lv *LocksView) SelectedBlocker() (db.LockWait, bool) {
+	selected := lv.tree.GetSelected()
+	if selected == nil {
+		return db.LockWait{}, false
+	}
+	wait, ok := selected.Data.(db.LockWait)
+	if !ok || len(wait.BlockedBy) > 0 {
+		return db.LockWait{}, false
+	}
+	return wait, true
+}
+
+// SetDimensions sets width and height
+func (lv *LocksView) SetDimensions(width, height int) {
+	lv.width = width
+	lv.height = height
+}
+
+// blockingTree nests each backend under those blocking it. Backends that
+// wait for nobody listed are roots; so is one member of each cycle, which
+// the server is about to break as a deadlock.
+func blockingTree(waits []db.LockWait, now time.Time) []*treeview.Node {
+	byPID := make(map[int]db.LockWait, len(waits))
+	for _, w := range waits {
+		byPID[w.PID] = w
+	}
+	blocks := make(map[int][]db.LockWait)
+	for _, w := range waits {
+		for _, pid := range w.BlockedBy {
+			if _, ok := byPID[pid]; ok {
+				blocks[pid] = append(blocks[pid], w)
+			}
+		}
+	}
+
+	visited := make(map[int]bool)
+	var build func(w db.LockWait, id string, path map[int]bool) *treeview.Node
+	build = func(w db.LockWait, id string, path map[int]bool) *treeview.Node {
+		visited[w.PID] = true
+		node := &treeview.Node{
+			ID:       id,
+			Label:    lockWaitLabel(w, now),
+			Expanded: true,
+			Data:     w,
+		}
+		path[w.PID] = true
+		for _, blocked := range blocks[w.PID] {
+			if path[blocked.PID] {
+				continue
+			}
+			node.Children = append(node.Children, build(blocked, id+"."+strconv.Itoa(blocked.PID), path))
+		}
+		delete(path, w.PID)
+		return node
+	}
+
+	var roots []*treeview.Node
+	addRoot := func(w db.LockWait) {
+		root := build(w, strconv.Itoa(w.PID), make(map[int]bool))
+		if len(w.BlockedBy) == 0 {
+			root.Color = lipgloss.Color("196")
+		}
+		roots = append(roots, root)
+	}
+	for _, w := range waits {
+		waitsForListed := false
+		for _, pid := range w.BlockedBy {
+			if _, ok := byPID[pid]; ok {
+				waitsForListed = true
+			}
+		}
+		if !waitsForListed {
+			addRoot(w)
+		}
+	}
+	for _, w := range waits {
+		if !visited[w.PID] {
+			addRoot(w)
+		}
+	}
+	return roots
+}
+
+// lockWaitLabel describes a backend on one line: who it is, the lock it
+// waits for and for how long, and its query
+func lockWaitLabel(w db.LockWait, now time.Time) string {
+	parts := []string{fmt.Sprintf("pid %d", w.PID)}
+	if w.User != "" || w.Application != "" {
+		parts = append(parts, w.User+"@"+w.Application)
+	}
+
+	switch {
+	case len(w.BlockedBy) == 0:
+		state := "blocker"
+		if w.State != "" {
+			state += ", " + w.State
+		}
+		parts = append(parts, state)
+	case w.Mode != "":
+		wait := "waits for " + w.Mode
+		if w.Relation != "" {
+			wait += " on " + w.Relation
+		} else {
+			wait += " (" + w.LockType + ")"
+		}
+		if !w.WaitStart.IsZero() {
+			wait += " for " + now.Sub(w.WaitStart).Round(time.Second).String()
+		}
+		parts = append(parts, wait)
+	default:
+		parts = append(parts, "waits")
+	}
+
+	if query := strings.Join(strings.Fields(w.Query), " "); query != "" {
+		parts = append(parts, query)
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,307 @@
+package connected
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// maxNotifications is how many notifications the view keeps, oldest dropped
+// first
+const maxNotifications = 1000
+
+// ListenerAction is what a command typed in the notifications view asks for
+type ListenerAction int
+
+const (
+	ListenerListen ListenerAction = iota
+	ListenerUnlisten
+	ListenerNotify
+)
+
+// ListenerRequestMsg is sent when a command is entered in the notifications
+// view
+type ListenerRequestMsg struct {
+	Action   ListenerAction
+	Channels []string // "*" unlistens from all
+	Payload  string   // for ListenerNotify, sent on Channels[0]
+}
+
+// NotificationsView streams the notifications received on the channels
+// listened on, with a command line to LISTEN, UNLISTEN and NOTIFY
+type NotificationsView struct {
+	notifications []db.Notification
+	channels      []string // listened on
+	connecting    bool     // the listener's connection is being opened
+	input         string
+	err           error
+	scroll        int  // first line shown when not following
+	follow        bool // keep the newest notification in view
+	width         int
+	height        int
+}
+
+// NewNotificationsView creates an empty notifications view
+func NewNotificationsView() *NotificationsView {
+	return &NotificationsView{
+		follow: true,
+	}
+}
+
+// Update handles messages
+func (nv *NotificationsView) Update(msg tea.Msg) (*NotificationsView, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nv, nil
+	}
+
+	lines := len(nv.lines())
+	switch keyMsg.Type {
+	case tea.KeyRunes, tea.KeySpace:
+		if !keyMsg.Alt {
+			nv.input += string(keyMsg.Runes)
+		}
+		return nv, nil
+	case tea.KeyBackspace:
+		if runes := []rune(nv.input); len(runes) > 0 {
+			nv.input = string(runes[:len(runes)-1])
+		}
+		return nv, nil
+	case tea.KeyEnter:
+		return nv, nv.submit()
+	case tea.KeyUp:
+		nv.scrollTo(nv.firstLine(lines) - 1)
+	case tea.KeyDown:
+		nv.scrollTo(nv.firstLine(lines) + 1)
+	case tea.KeyPgUp:
+		nv.scrollTo(nv.firstLine(lines) - nv.visibleLines())
+	case tea.KeyPgDown:
+		nv.scrollTo(nv.firstLine(lines) + nv.visibleLines())
+	case tea.KeyHome:
+		nv.scrollTo(0)
+	case tea.KeyEnd:
+		nv.follow = true
+	}
+	return nv, nil
+}
+
+// submit parses the command line and clears it
+func (nv *NotificationsView) submit() tea.Cmd {
+	request, err := parseListenerCommand(nv.input)
+	if err != nil {
+		nv.err = err
+		return nil
+	}
+	if nv.connecting && request.Action != ListenerNotify {
+		nv.err = fmt.Errorf("the listener is still connecting")
+		return nil
+	}
+
+	nv.input = ""
+	nv.err = nil
+	return func() tea.Msg { return request }
+}
+
+// parseListenerCommand parses "listen a b", "unlisten a" (or "*", the
+// default) and "notify channel payload"
+func parseListenerCommand(input string) (ListenerRequestMsg, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return ListenerRequestMsg{}, fmt.Errorf("type listen, unlisten or notify")
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "listen":
+		if len(fields) < 2 {
+			return ListenerRequestMsg{}, fmt.Errorf("listen takes one or more channels")
+		}
+		return ListenerRequestMsg{Action: ListenerListen, Channels: fields[1:]}, nil
+	case "unlisten":
+		channels := fields[1:]
+		if len(channels) == 0 {
+			channels = []string{"*"}
+		}
+		return ListenerRequestMsg{Action: ListenerUnlisten, Channels: channels}, nil
+	case "notify":
+		if len(fields) < 2 {
+			return ListenerRequestMsg{}, fmt.Errorf("notify takes a channel and a payload")
+		}
+		// The payload is the rest of the line, spacing and all
+		rest := strings.TrimLeft(strings.TrimSpace(input)[len(fields[0]):], " \t")
+		payload := strings.TrimLeft(rest[len(fields[1]):], " \t")
+		return ListenerRequestMsg{Action: ListenerNotify, Channels: fields[1:2], Payload: payload}, nil
+	}
+	return ListenerRequestMsg{}, fmt.Errorf("unknown command %q: type listen, unlisten or notify", fields[0])
+}
+
+// View renders the notifications and the command line
+func (nv *NotificationsView) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("62")).
+		Bold(true).
+		PaddingLeft(1)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		PaddingLeft(1)
+
+	listening := "not listening"
+	switch {
+	case nv.connecting:
+		listening = "connecting..."
+	case len(nv.channels) > 0:
+		listening = "listening on " + strings.Join(nv.channels, ", ")
+	}
+	title := titleStyle.Render(fmt.Sprintf("Notifications (%d)", len(nv.notifications))) +
+		dimStyle.Render(listening+" · ↑/↓ scroll · End: follow · Esc: back")
+
+	var body string
+	lines := nv.lines()
+	if len(lines) == 0 {
+		body = dimStyle.Render("No notifications yet")
+	} else {
+		first := nv.firstLine(len(lines))
+		end := min(first+nv.visibleLines(), len(lines))
+		width := max(nv.width-2, 10)
+		for i := first; i < end; i++ {
+			lines[i] = " " + truncateString(lines[i], width)
+		}
+		body = strings.Join(lines[first:end], "\n")
+	}
+	body = lipgloss.NewStyle().Height(nv.visibleLines()).Render(body)
+
+	status := dimStyle.Render("listen <channels> · unlisten [channels|*] · notify <channel> <payload>")
+	if nv.err != nil {
+		status = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			PaddingLeft(1).
+			Render(truncateString("Error: "+nv.err.Error(), max(nv.width-2, 10)))
+	}
+	prompt := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("63")).
+		PaddingLeft(1).
+		Render("> " + nv.input + "█")
+
+	return title + "\n\n" + body + "\n" + status + "\n" + prompt
+}
+
+// lines renders every notification: when it arrived, its channel and
+// sender on one line, then its payload, pretty-printed if it's JSON
+func (nv *NotificationsView) lines() []string {
+	var lines []string
+	for _, n := range nv.notifications {
+		header := fmt.Sprintf("%s  %s  pid %d", n.Received.Format("15:04:05.000"), n.Channel, n.PID)
+
+		var pretty bytes.Buffer
+		trimmed := strings.TrimSpace(n.Payload)
+		isJSON := strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")
+		if isJSON && json.Indent(&pretty, []byte(trimmed), "  ", "  ") == nil {
+			lines = append(lines, header)
+			lines = append(lines, strings.Split("  "+pretty.String(), "\n")...)
+			continue
+		}
+
+		payload := strings.Join(strings.Fields(n.Payload), " ")
+		if payload != "" {
+			header += "  " + payload
+		}
+		lines = append(lines, header)
+	}
+	return lines
+}
+
+// firstLine is the first of total lines in view
+func (nv *NotificationsView) firstLine(total int) int {
+	last := max(total-nv.visibleLines(), 0)
+	if nv.follow {
+		return last
+	}
+	return min(nv.scroll, last)
+}
+
+// scrollTo shows line first at the top, following new notifications once
+// scrolled to the bottom
+func (nv *NotificationsView) scrollTo(first int) {
+	last := max(len(nv.lines())-nv.visibleLines(), 0)
+	nv.scroll = min(max(first, 0), last)
+	nv.follow = nv.scroll == last
+}
+
+// visibleLines is the number of lines between the title and the command
+// line
+func (nv *NotificationsView) visibleLines() int {
+	return max(nv.height-5, 1)
+}
+
+// SetConnecting shows the listener's connection being opened
+func (nv *NotificationsView) SetConnecting(connecting bool) {
+	nv.connecting = connecting
+}
+
+// Connecting reports whether the listener's connection is being opened
+func (nv *NotificationsView) Connecting() bool {
+	return nv.connecting
+}
+
+// AddNotification appends a received notification
+func (nv *NotificationsView) AddNotification(n db.Notification) {
+	nv.notifications = append(nv.notifications, n)
+	if len(nv.notifications) > maxNotifications {
+		nv.notifications = nv.notifications[len(nv.notifications)-maxNotifications:]
+	}
+}
+
+// SetError shows err below the notifications
+func (nv *NotificationsView) SetError(err error) {
+	nv.err = err
+}
+
+// SetListening records channels as listened on
+func (nv *NotificationsView) SetListening(channels ...string) {
+	for _, channel := range channels {
+		if !nv.IsListening(channel) {
+			nv.channels = append(nv.channels, channel)
+		}
+	}
+}
+
+// SetUnlistened records channels, or all with "*", as no longer listened on
+func (nv *NotificationsView) SetUnlistened(channels ...string) {
+	for _, channel := range channels {
+		if channel == "*" {
+			nv.channels = nil
+			return
+		}
+		for i, c := range nv.channels {
+			if c == channel {
+				nv.channels = append(nv.channels[:i], nv.channels[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// IsListening reports whether channel is listened on
+func (nv *NotificationsView) IsListening(channel string) bool {
+	for _, c := range nv.channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// Channels returns the channels listened on
+func (nv *NotificationsView) Channels() []string {
+	return nv.channels
+}
+
+// SetDimensions sets width and height
+func (nv *NotificationsView) SetDimensions(width, height int) {
+	nv.width = width
+	nv.height = height
+}
@@ -0,0 +1,198 @@
+package connected
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/imran-vz/gosqlit/pkg/treeview"
+)
+
+// PlanView shows a query plan as a collapsible tree. Nodes taking a large
+// share of the time, or of the cost when the plan wasn't analyzed, are
+// highlighted.
+type PlanView struct {
+	tree    *treeview.Tree
+	plan    db.Plan
+	analyze bool
+	loading bool
+	err     error
+	width   int
+	height  int
+}
+
+// planShareColors colours a node by its share of the whole plan, largest
+// first
+var planShareColors = []struct {
+	share float64
+	color lipgloss.Color
+}{
+	{0.5, lipgloss.Color("196")},
+	{0.2, lipgloss.Color("208")},
+	{0.1, lipgloss.Color("220")},
+}
+
+// NewPlanView creates an empty plan view
+func NewPlanView() *PlanView {
+	return &PlanView{
+		tree: treeview.NewTree(nil),
+	}
+}
+
+// Update handles messages
+func (pv *PlanView) Update(msg tea.Msg) (*PlanView, tea.Cmd) {
+	newTree, cmd := pv.tree.Update(msg)
+	pv.tree = newTree
+	return pv, cmd
+}
+
+// View renders the plan
+func (pv *PlanView) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("62")).
+		Bold(true).
+		PaddingLeft(1)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		PaddingLeft(1)
+
+	heading := "EXPLAIN"
+	if pv.analyze {
+		heading = "EXPLAIN ANALYZE"
+	}
+	if pv.plan.Analyzed && !pv.loading && pv.err == nil {
+		heading += fmt.Sprintf(" · planning %.3f ms · execution %.3f ms", pv.plan.PlanningTime, pv.plan.ExecutionTime)
+	}
+	title := titleStyle.Render(heading) + dimStyle.Render("(Esc: back to results)")
+
+	switch {
+	case pv.loading:
+		return title + "\n\n" + dimStyle.Render("Running EXPLAIN...")
+	case pv.err != nil:
+		return title + "\n\n" + lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			PaddingLeft(1).
+			Width(max(pv.width-2, 20)).
+			Render("Error: "+pv.err.Error())
+	}
+
+	// The selected node's conditions don't fit on its line
+	detail := ""
+	if selected := pv.tree.GetSelected(); selected != nil {
+		if node, ok := selected.Data.(db.PlanNode); ok {
+			detail = node.Detail
+		}
+	}
+
+	content := lipgloss.NewStyle().
+		PaddingLeft(1).
+		Render(pv.tree.View(max(pv.width-2, 10), max(pv.height-5, 1)))
+	return title + "\n\n" + content + "\n\n" + dimStyle.Render(truncateString(detail, max(pv.width-2, 10)))
+}
+
+// SetLoading clears the view while a plan is fetched
+func (pv *PlanView) SetLoading(analyze bool) {
+	pv.analyze = analyze
+	pv.loading = true
+	pv.err = nil
+	pv.plan = db.Plan{}
+	pv.tree.SetRoot(nil)
+}
+
+// SetPlan shows plan, or the error explaining the statement failed with
+func (pv *PlanView) SetPlan(plan db.Plan, err error) {
+	pv.loading = false
+	pv.err = err
+	pv.plan = plan
+	if err != nil {
+		return
+	}
+
+	// Shares are of the whole plan's time, or its cost if not analyzed
+	total := plan.Root.TotalCost
+	if plan.Analyzed {
+		total = plan.Root.TotalTime()
+	}
+	pv.tree.SetRoot(planTreeNode(plan.Root, plan.Analyzed, total, "0"))
+}
+
+// SetDimensions sets width and height
+func (pv *PlanView) SetDimensions(width, height int) {
+	pv.width = width
+	pv.height = height
+}
+
+// planTreeNode builds the tree node of a plan node and its children, all
+// expanded
+func planTreeNode(node db.PlanNode, analyzed bool, total float64, id string) *treeview.Node {
+	self := node.SelfCost()
+	if analyzed {
+		self = node.SelfTime()
+	}
+	share := 0.0
+	if total > 0 {
+		share = self / total
+	}
+
+	treeNode := &treeview.Node{
+		ID:       id,
+		Label:    planNodeLabel(node, analyzed, share),
+		Expanded: true,
+		Data:     node,
+	}
+	for _, shareColor := range planShareColors {
+		if share >= shareColor.share {
+			treeNode.Color = shareColor.color
+			break
+		}
+	}
+
+	for i, child := range node.Children {
+		treeNode.Children = append(treeNode.Children, planTreeNode(child, analyzed, total, id+"."+strconv.Itoa(i)))
+	}
+	return treeNode
+}
+
+// planNodeLabel summarizes a node on one line: what it does, its estimated
+// cost and rows, and when analyzed the actual rows, time and buffers
+func planNodeLabel(node db.PlanNode, analyzed bool, share float64) string {
+	name := node.NodeType
+	if node.Relation != "" {
+		name += " " + node.Relation
+	}
+
+	parts := []string{
+		name,
+		fmt.Sprintf("cost=%.2f..%.2f", node.StartupCost, node.TotalCost),
+	}
+
+	switch {
+	case !analyzed:
+		parts = append(parts, "rows="+formatPlanNumber(node.PlanRows))
+	case node.ActualLoops == 0:
+		parts = append(parts, "rows=? of "+formatPlanNumber(node.PlanRows)+" est", "(never executed)")
+	default:
+		rows := "rows=" + formatPlanNumber(node.ActualRows) + " of " + formatPlanNumber(node.PlanRows) + " est"
+		if node.ActualLoops > 1 {
+			rows += " ×" + formatPlanNumber(node.ActualLoops) + " loops"
+		}
+		parts = append(parts, rows, fmt.Sprintf("time=%.3f ms", node.TotalTime()))
+
+		buffers := fmt.Sprintf("buffers hit=%d read=%d", node.SharedHitBlocks, node.SharedReadBlocks)
+		if node.TempReadBlocks > 0 || node.TempWriteBlocks > 0 {
+			buffers += fmt.Sprintf(" temp=%d/%d", node.TempReadBlocks, node.TempWriteBlocks)
+		}
+		parts = append(parts, buffers)
+	}
+
+	parts = append(parts, fmt.Sprintf("%.0f%%", share*100))
+	return strings.Join(parts, "  ")
+}
+
+// formatPlanNumber prints a row or loop count without trailing zeros
+func formatPlanNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
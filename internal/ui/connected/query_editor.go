@@ -1,6 +1,7 @@
 package connected
 
 import (
+	"fmt"
 	"os/exec"
 	"regexp"
 	"runtime"
@@ -365,6 +366,35 @@ func getClipboardContent() string {
 	return string(output)
 }
 
+// CopyToClipboard puts text on the system clipboard, with the same tools
+// pasting reads it with
+func CopyToClipboard(text string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin": // macOS
+		cmd = exec.Command("pbcopy")
+	case "linux":
+		if _, err := exec.LookPath("xclip"); err == nil {
+			cmd = exec.Command("xclip", "-selection", "clipboard", "-i")
+		} else if _, err := exec.LookPath("xsel"); err == nil {
+			cmd = exec.Command("xsel", "--clipboard", "--input")
+		} else {
+			return fmt.Errorf("no clipboard tool found, install xclip or xsel")
+		}
+	case "windows":
+		cmd = exec.Command("clip")
+	default:
+		return fmt.Errorf("clipboard is not supported on %s", runtime.GOOS)
+	}
+
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	return nil
+}
+
 // cleanClipboardContent removes brackets and cleans clipboard content
 func cleanClipboardContent(content string) string {
 	// Remove square brackets []
@@ -7,19 +7,25 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/imran-vz/gosqlit/internal/db"
+	"github.com/imran-vz/gosqlit/internal/format"
 )
 
-// ResultsTable displays query results
+// ResultsTable displays query results, one tab per statement of a script,
+// and a Messages tab with the notices the server sent while running it
 type ResultsTable struct {
-	columns  []string
-	rows     [][]any
-	hasMore  bool
-	page     int
-	pageSize int
-	cursor   int
-	scroll   int
-	width    int
-	height   int
+	sets      []db.QueryResultSet
+	active    int
+	columns   []db.Column // of the active set
+	rows      [][]any     // of the active set
+	page      int
+	pageSize  int
+	cursor    int
+	scroll    int
+	messages  bool // the Messages tab is shown instead of the active set
+	open      int  // set whose cursor is still open for load more, -1 if none
+	msgScroll int
+	width     int
+	height    int
 }
 
 // NewResultsTable creates results table
@@ -27,11 +33,17 @@ func NewResultsTable() *ResultsTable {
 	return &ResultsTable{
 		pageSize: 50,
 		page:     0,
+		open:     -1,
 	}
 }
 
 // Update handles messages
 func (rt *ResultsTable) Update(msg tea.Msg) (*ResultsTable, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && rt.messages {
+		rt.updateMessages(keyMsg)
+		return rt, nil
+	}
+
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
 		switch keyMsg.String() {
 		case "up", "k":
@@ -57,6 +69,17 @@ func (rt *ResultsTable) Update(msg tea.Msg) (*ResultsTable, tea.Cmd) {
 			rt.cursor = 0
 		case "end":
 			rt.cursor = max(len(rt.rows)-1, 0)
+		case "[":
+			if rt.active > 0 {
+				rt.selectSet(rt.active - 1)
+			}
+		case "]":
+			if rt.active < len(rt.sets)-1 {
+				rt.selectSet(rt.active + 1)
+			} else if rt.noticeCount() > 0 {
+				rt.messages = true
+				rt.msgScroll = 0
+			}
 		}
 
 		// Adjust scroll
@@ -81,14 +104,35 @@ func (rt *ResultsTable) View() string {
 		PaddingLeft(1)
 
 	title := titleStyle.Render(fmt.Sprintf("Results (%d rows)", len(rt.rows)))
+	if len(rt.sets) > 1 || rt.noticeCount() > 0 {
+		title = rt.renderSetTabs()
+	}
+
+	if rt.messages {
+		return title + "\n\n" + strings.Join(rt.visibleMessageLines(), "\n")
+	}
 
 	if len(rt.columns) == 0 {
-		noData := lipgloss.NewStyle().
+		text := "No query executed yet"
+		style := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("240")).
-			PaddingLeft(1).
-			Render("No query executed yet")
+			PaddingLeft(1)
+
+		if set := rt.activeSet(); set != nil {
+			switch {
+			case set.Err != nil:
+				text = "Error: " + set.Err.Error()
+				style = style.Foreground(lipgloss.Color("196")).Width(max(rt.width-2, 20))
+			case set.CommandTag != "" && set.RowsAffected > 0:
+				text = fmt.Sprintf("%s (%d rows affected)", set.CommandTag, set.RowsAffected)
+			case set.CommandTag != "":
+				text = set.CommandTag
+			default:
+				text = "OK"
+			}
+		}
 
-		return title + "\n\n" + noData
+		return title + "\n\n" + style.Render(text)
 	}
 
 	// Available width for the table content (accounting for borders/padding)
@@ -101,7 +145,7 @@ func (rt *ResultsTable) View() string {
 	var output strings.Builder
 
 	// Render header row
-	headerRow := rt.renderRow(rt.columns, colWidths, true, false)
+	headerRow := rt.renderRow(rt.headers(), nil, colWidths, true, false)
 	output.WriteString(headerRow)
 	output.WriteString("\n")
 
@@ -122,11 +166,13 @@ func (rt *ResultsTable) View() string {
 	for i := rt.scroll; i < end; i++ {
 		row := rt.rows[i]
 		rowStrings := make([]string, len(row))
+		nulls := make([]bool, len(row))
 		for j, cell := range row {
-			rowStrings[j] = sanitizeCellContent(fmt.Sprintf("%v", cell))
+			rowStrings[j] = rt.cellText(j, cell)
+			nulls[j] = cell == nil
 		}
 		isSelected := i == rt.cursor
-		rowLine := rt.renderRow(rowStrings, colWidths, false, isSelected)
+		rowLine := rt.renderRow(rowStrings, nulls, colWidths, false, isSelected)
 		output.WriteString(rowLine)
 		output.WriteString("\n")
 	}
@@ -138,11 +184,16 @@ func (rt *ResultsTable) View() string {
 			Foreground(lipgloss.Color("240")).
 			PaddingLeft(1).
 			Render("0 rows")
-	} else if rt.hasMore {
+	} else if rt.CanLoadMore() {
 		footer = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("240")).
 			PaddingLeft(1).
 			Render(fmt.Sprintf("Showing %d-%d of %d+ rows (Ctrl+L to load more)", rt.scroll+1, end, len(rt.rows)))
+	} else if rt.HasMore() {
+		footer = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			PaddingLeft(1).
+			Render(fmt.Sprintf("Showing %d-%d of %d+ rows (cursor closed, run the query again for more)", rt.scroll+1, end, len(rt.rows)))
 	} else {
 		footer = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("240")).
@@ -169,9 +220,9 @@ func (rt *ResultsTable) calculateColumnWidths(availableWidth int) []int {
 	minWidths := make([]int, numCols) // minimum width per column
 
 	// Start with header widths
-	for i, col := range rt.columns {
-		naturalWidths[i] = runeWidth(col)
-		minWidths[i] = min(3, runeWidth(col)) // minimum 3 chars or header length
+	for i, header := range rt.headers() {
+		naturalWidths[i] = runeWidth(header)
+		minWidths[i] = min(3, runeWidth(header)) // minimum 3 chars or header length
 	}
 
 	// Check row widths (sample first 100 rows for performance)
@@ -185,8 +236,7 @@ func (rt *ResultsTable) calculateColumnWidths(availableWidth int) []int {
 			if i >= numCols {
 				break
 			}
-			cellStr := sanitizeCellContent(fmt.Sprintf("%v", cell))
-			w := runeWidth(cellStr)
+			w := runeWidth(rt.cellText(i, cell))
 			if w > naturalWidths[i] {
 				naturalWidths[i] = w
 			}
@@ -239,8 +289,9 @@ func (rt *ResultsTable) calculateColumnWidths(availableWidth int) []int {
 	return finalWidths
 }
 
-// renderRow renders a single row with proper column alignment
-func (rt *ResultsTable) renderRow(cells []string, colWidths []int, isHeader, isSelected bool) string {
+// renderRow renders a single row with proper column alignment, dimming
+// cells that are NULL
+func (rt *ResultsTable) renderRow(cells []string, nulls []bool, colWidths []int, isHeader, isSelected bool) string {
 	parts := make([]string, len(colWidths))
 
 	for i := range colWidths {
@@ -254,31 +305,42 @@ func (rt *ResultsTable) renderRow(cells []string, colWidths []int, isHeader, isS
 			cellContent = truncateString(cellContent, colWidths[i])
 		}
 
-		// Pad to width
-		cellContent = padToWidth(cellContent, colWidths[i])
+		// Pad to width, numbers on the left so their digits line up
+		if i < len(rt.columns) && rt.columns[i].Kind == db.KindNumber {
+			cellContent = padLeftToWidth(cellContent, colWidths[i])
+		} else {
+			cellContent = padToWidth(cellContent, colWidths[i])
+		}
 		parts[i] = cellContent
 	}
 
-	row := strings.Join(parts, " │ ")
-
 	if isHeader {
 		return lipgloss.NewStyle().
 			Foreground(lipgloss.Color("63")).
 			Bold(true).
 			PaddingLeft(1).
-			Render(row)
+			Render(strings.Join(parts, " │ "))
 	}
 
+	// Cells are styled one by one so a NULL doesn't end the selection
+	// background for the rest of the row
+	style := lipgloss.NewStyle()
 	if isSelected {
-		return lipgloss.NewStyle().
-			Background(lipgloss.Color("237")).
-			PaddingLeft(1).
-			Render(row)
+		style = style.Background(lipgloss.Color("237"))
 	}
+	nullStyle := style.Foreground(lipgloss.Color("240")).Italic(true)
 
-	return lipgloss.NewStyle().
+	for i := range parts {
+		if i < len(nulls) && nulls[i] {
+			parts[i] = nullStyle.Render(parts[i])
+		} else {
+			parts[i] = style.Render(parts[i])
+		}
+	}
+
+	return style.
 		PaddingLeft(1).
-		Render(row)
+		Render(strings.Join(parts, style.Render(" │ ")))
 }
 
 // renderSeparator renders the separator line between header and data
@@ -297,19 +359,277 @@ func (rt *ResultsTable) renderSeparator(colWidths []int) string {
 		Render(separator)
 }
 
-// SetData sets table data
+// renderSetTabs renders one tab per result set, highlighting the active one
+func (rt *ResultsTable) renderSetTabs() string {
+	activeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("62")).
+		Bold(true).
+		Underline(true)
+	inactiveStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("245"))
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196"))
+
+	tabs := make([]string, len(rt.sets))
+	for i, set := range rt.sets {
+		var label string
+		switch {
+		case set.Err != nil:
+			label = fmt.Sprintf("%d ✗ error", i+1)
+		case set.Columns != nil:
+			label = fmt.Sprintf("%d (%d rows)", i+1, len(set.Rows))
+		case set.CommandTag != "":
+			label = fmt.Sprintf("%d %s", i+1, set.CommandTag)
+		default:
+			label = fmt.Sprintf("%d OK", i+1)
+		}
+
+		switch {
+		case i == rt.active && !rt.messages:
+			tabs[i] = activeStyle.Render(label)
+		case set.Err != nil:
+			tabs[i] = errorStyle.Render(label)
+		default:
+			tabs[i] = inactiveStyle.Render(label)
+		}
+	}
+
+	if count := rt.noticeCount(); count > 0 {
+		label := fmt.Sprintf("Messages (%d)", count)
+		if rt.messages {
+			tabs = append(tabs, activeStyle.Render(label))
+		} else {
+			tabs = append(tabs, inactiveStyle.Render(label))
+		}
+	}
+
+	return lipgloss.NewStyle().
+		PaddingLeft(1).
+		MaxWidth(max(rt.width, 20)).
+		Render(strings.Join(tabs, " │ ") + inactiveStyle.Render("  ([ ] to switch)"))
+}
+
+// SetData sets table data from a single query
 func (rt *ResultsTable) SetData(result db.QueryResult) {
-	rt.columns = result.Columns
-	rt.rows = result.Rows
-	rt.hasMore = result.HasMore
+	rt.SetResultSets([]db.QueryResultSet{{
+		Columns: result.Columns,
+		Rows:    result.Rows,
+		HasMore: result.HasMore,
+	}})
+}
+
+// SetResultSets shows the results of a script, starting at the first failed
+// statement or else the last one that returned rows. A script that did
+// neither but sent notices, like a DO block with RAISE NOTICE, starts at
+// its messages. Only the last statement's cursor is left open; each
+// statement closes the one before it.
+func (rt *ResultsTable) SetResultSets(sets []db.QueryResultSet) {
+	rt.sets = sets
+	rt.open = len(sets) - 1
+	rt.messages = false
+	rt.msgScroll = 0
+
+	active := len(sets) - 1
+	for i := len(sets) - 1; i >= 0; i-- {
+		if sets[i].Columns != nil {
+			active = i
+			break
+		}
+	}
+	for i, set := range sets {
+		if set.Err != nil {
+			active = i
+			break
+		}
+	}
+
+	rt.selectSet(max(active, 0))
+
+	if rt.noticeCount() > 0 {
+		rt.messages = true
+		for _, set := range sets {
+			if set.Columns != nil || set.Err != nil {
+				rt.messages = false
+				break
+			}
+		}
+	}
+}
+
+// AppendData appends more rows to result set index (for load more)
+func (rt *ResultsTable) AppendData(index int, result db.QueryResult) {
+	if index < 0 || index >= len(rt.sets) {
+		return
+	}
+
+	set := &rt.sets[index]
+	set.Rows = append(set.Rows, result.Rows...)
+	set.HasMore = result.HasMore
+	if index == rt.active {
+		rt.rows = set.Rows
+	}
+}
+
+// AddNotices adds notices sent while loading more rows of result set index
+func (rt *ResultsTable) AddNotices(index int, notices []db.Notice) {
+	if index < 0 || index >= len(rt.sets) {
+		return
+	}
+	rt.sets[index].Notices = append(rt.sets[index].Notices, notices...)
+}
+
+// ActiveSet returns the index and SQL of the result set being shown
+func (rt *ResultsTable) ActiveSet() (int, string) {
+	if set := rt.activeSet(); set != nil {
+		return rt.active, set.SQL
+	}
+	return 0, ""
+}
+
+// HasMore returns true if the shown query has more rows than were fetched
+func (rt *ResultsTable) HasMore() bool {
+	if set := rt.activeSet(); set != nil {
+		return set.HasMore
+	}
+	return false
+}
+
+// CanLoadMore returns true if the shown query has more rows and its cursor
+// is still open. Fetching them any other way would re-run the query on a
+// new snapshot and could skip or repeat rows.
+func (rt *ResultsTable) CanLoadMore() bool {
+	return rt.HasMore() && rt.active == rt.open
+}
+
+// CloseCursor records that another statement ran on the session, closing
+// the cursor of the shown results
+func (rt *ResultsTable) CloseCursor() {
+	rt.open = -1
+}
+
+// activeSet returns the result set being shown, nil before the first query
+func (rt *ResultsTable) activeSet() *db.QueryResultSet {
+	if rt.active < len(rt.sets) {
+		return &rt.sets[rt.active]
+	}
+	return nil
+}
+
+// selectSet shows result set index
+func (rt *ResultsTable) selectSet(index int) {
+	rt.active = index
+	rt.columns = nil
+	rt.rows = nil
+	if set := rt.activeSet(); set != nil {
+		rt.columns = set.Columns
+		rt.rows = set.Rows
+	}
 	rt.cursor = 0
 	rt.scroll = 0
 }
 
-// AppendData appends more rows (for load more)
-func (rt *ResultsTable) AppendData(result db.QueryResult) {
-	rt.rows = append(rt.rows, result.Rows...)
-	rt.hasMore = result.HasMore
+// noticeCount returns the number of notices sent while the script ran
+func (rt *ResultsTable) noticeCount() int {
+	count := 0
+	for _, set := range rt.sets {
+		count += len(set.Notices)
+	}
+	return count
+}
+
+// updateMessages scrolls the Messages tab, or leaves it with [
+func (rt *ResultsTable) updateMessages(keyMsg tea.KeyMsg) {
+	visible := max(rt.height-4, 1)
+	switch keyMsg.String() {
+	case "up", "k":
+		rt.msgScroll--
+	case "down", "j":
+		rt.msgScroll++
+	case "pageup":
+		rt.msgScroll -= visible
+	case "pagedown":
+		rt.msgScroll += visible
+	case "home":
+		rt.msgScroll = 0
+	case "end":
+		rt.msgScroll = len(rt.messageLines())
+	case "[":
+		rt.messages = false
+	}
+	rt.msgScroll = min(max(rt.msgScroll, 0), max(len(rt.messageLines())-visible, 0))
+}
+
+// visibleMessageLines returns the lines of the Messages tab in view
+func (rt *ResultsTable) visibleMessageLines() []string {
+	lines := rt.messageLines()
+	end := min(rt.msgScroll+max(rt.height-4, 1), len(lines))
+	return lines[min(rt.msgScroll, end):end]
+}
+
+// messageLines renders the notices like psql does, each prefixed with its
+// statement's number when the script had several, and wrapped to the pane
+func (rt *ResultsTable) messageLines() []string {
+	width := max(rt.width-2, 20)
+	prefixStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	textStyle := lipgloss.NewStyle().PaddingLeft(1).Width(width)
+
+	var lines []string
+	for i, set := range rt.sets {
+		for _, notice := range set.Notices {
+			prefix := ""
+			if len(rt.sets) > 1 {
+				prefix = fmt.Sprintf("[%d] ", i+1)
+			}
+
+			severityStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+			switch notice.Severity {
+			case "WARNING":
+				severityStyle = severityStyle.Foreground(lipgloss.Color("208"))
+			case "DEBUG", "LOG":
+				severityStyle = severityStyle.Foreground(lipgloss.Color("244"))
+			}
+
+			text := prefixStyle.Render(prefix) + severityStyle.Render(notice.Severity+":  "+notice.Message)
+			for _, field := range []struct{ name, value string }{
+				{"DETAIL", notice.Detail},
+				{"HINT", notice.Hint},
+				{"CONTEXT", notice.Where},
+			} {
+				if field.value != "" {
+					text += "\n" + prefixStyle.Render(strings.Repeat(" ", len(prefix))+field.name+":  "+field.value)
+				}
+			}
+			lines = append(lines, strings.Split(textStyle.Render(text), "\n")...)
+		}
+	}
+	return lines
+}
+
+// headers returns the header text of the shown columns, marking those whose
+// values map back to a table column
+func (rt *ResultsTable) headers() []string {
+	headers := make([]string, len(rt.columns))
+	for i, col := range rt.columns {
+		headers[i] = col.Name
+		if col.Editable() {
+			headers[i] = "✎ " + col.Name
+		}
+	}
+	return headers
+}
+
+// cellText renders the value of column i for display
+func (rt *ResultsTable) cellText(i int, v any) string {
+	var col db.Column
+	if i < len(rt.columns) {
+		col = rt.columns[i]
+	}
+	return sanitizeCellContent(format.Value(v, col))
+}
+
+// RowCount returns the number of rows loaded so far
+func (rt *ResultsTable) RowCount() int {
+	return len(rt.rows)
 }
 
 // SetDimensions sets width and height
@@ -344,6 +664,15 @@ func padToWidth(s string, width int) string {
 	return s + strings.Repeat(" ", width-currentWidth)
 }
 
+// padLeftToWidth pads a string with leading spaces to reach the target width
+func padLeftToWidth(s string, width int) string {
+	currentWidth := runeWidth(s)
+	if currentWidth >= width {
+		return s
+	}
+	return strings.Repeat(" ", width-currentWidth) + s
+}
+
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
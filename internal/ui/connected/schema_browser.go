@@ -1,6 +1,8 @@
 package connected
 
 import (
+	"fmt"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/imran-vz/gosqlit/internal/db"
@@ -58,7 +60,8 @@ func (sb *SchemaBrowser) View() string {
 	return title + "\n\n" + content
 }
 
-// SetSchemas populates tree with schemas
+// SetSchemas populates tree with schemas. The objects of schemas listed
+// without them are loaded when the schema is expanded.
 func (sb *SchemaBrowser) SetSchemas(schemas []db.Schema) {
 	root := &treeview.Node{
 		ID:       "root",
@@ -74,16 +77,11 @@ func (sb *SchemaBrowser) SetSchemas(schemas []db.Schema) {
 			Children: []*treeview.Node{},
 			Expanded: false,
 			Data:     schema,
+			Lazy:     schema.Tables == nil,
 		}
 
-		for _, table := range schema.Tables {
-			tableNode := &treeview.Node{
-				ID:       "table:" + schema.Name + "." + table.Name,
-				Label:    "📄 " + table.Name,
-				Children: []*treeview.Node{},
-				Data:     table,
-			}
-			schemaNode.Children = append(schemaNode.Children, tableNode)
+		if schema.Tables != nil {
+			schemaNode.Children = objectFolders(schema)
 		}
 
 		root.Children = append(root.Children, schemaNode)
@@ -93,6 +91,73 @@ func (sb *SchemaBrowser) SetSchemas(schemas []db.Schema) {
 	sb.loading = false
 }
 
+// SetSchemaTables fills in the objects of a schema being loaded. Results
+// for a schema that isn't loading, e.g. after a refresh, are dropped.
+func (sb *SchemaBrowser) SetSchemaTables(schema string, tables []db.Table) {
+	node := sb.tree.FindNode("schema:" + schema)
+	if node == nil || !node.Loading {
+		return
+	}
+
+	loaded := db.Schema{Name: schema, Tables: tables}
+	node.Data = loaded
+	sb.tree.SetChildren(node, objectFolders(loaded))
+}
+
+// SchemaLoadFailed collapses a schema whose objects couldn't be loaded
+func (sb *SchemaBrowser) SchemaLoadFailed(schema string) {
+	if node := sb.tree.FindNode("schema:" + schema); node != nil && node.Loading {
+		sb.tree.LoadFailed(node)
+	}
+}
+
+// objectStyles are the folder labels and icons of each object kind
+var objectStyles = map[db.ObjectKind]struct{ folder, icon string }{
+	db.ObjectTable:            {"Tables", "📄"},
+	db.ObjectView:             {"Views", "🔍"},
+	db.ObjectMaterializedView: {"Materialized Views", "💾"},
+	db.ObjectForeignTable:     {"Foreign Tables", "🌐"},
+	db.ObjectFunction:         {"Functions", "🔧"},
+	db.ObjectProcedure:        {"Procedures", "📜"},
+	db.ObjectSequence:         {"Sequences", "🔢"},
+	db.ObjectEnum:             {"Enums", "🔖"},
+	db.ObjectType:             {"Types", "🧩"},
+}
+
+// objectFolders groups a schema's objects into a folder per kind, leaving
+// out kinds it has none of
+func objectFolders(schema db.Schema) []*treeview.Node {
+	byKind := make(map[db.ObjectKind][]db.Table)
+	for _, table := range schema.Tables {
+		byKind[table.Kind] = append(byKind[table.Kind], table)
+	}
+
+	folders := []*treeview.Node{}
+	for _, kind := range db.ObjectKinds {
+		objects := byKind[kind]
+		if len(objects) == 0 {
+			continue
+		}
+
+		style := objectStyles[kind]
+		folder := &treeview.Node{
+			ID:       fmt.Sprintf("folder:%s:%d", schema.Name, kind),
+			Label:    fmt.Sprintf("📁 %s (%d)", style.folder, len(objects)),
+			Children: []*treeview.Node{},
+		}
+		for _, object := range objects {
+			folder.Children = append(folder.Children, &treeview.Node{
+				ID:       fmt.Sprintf("object:%d:%s.%s%s", kind, schema.Name, object.Name, object.Signature),
+				Label:    style.icon + " " + object.Name + object.Signature,
+				Children: []*treeview.Node{},
+				Data:     object,
+			})
+		}
+		folders = append(folders, folder)
+	}
+	return folders
+}
+
 // GetSelectedTable returns selected table if any
 func (sb *SchemaBrowser) GetSelectedTable() (schema, table string, ok bool) {
 	selected := sb.tree.GetSelected()
@@ -100,13 +165,24 @@ func (sb *SchemaBrowser) GetSelectedTable() (schema, table string, ok bool) {
 		return "", "", false
 	}
 
-	if tbl, ok := selected.Data.(db.Table); ok {
+	// Only relations can be selected from
+	if tbl, ok := selected.Data.(db.Table); ok && tbl.Kind.IsRelation() {
 		return tbl.Schema, tbl.Name, true
 	}
 
 	return "", "", false
 }
 
+// GetSelectedObject returns the selected schema object of any kind
+func (sb *SchemaBrowser) GetSelectedObject() (db.Table, bool) {
+	selected := sb.tree.GetSelected()
+	if selected == nil {
+		return db.Table{}, false
+	}
+	object, ok := selected.Data.(db.Table)
+	return object, ok
+}
+
 // SetDimensions sets width and height
 func (sb *SchemaBrowser) SetDimensions(width, height int) {
 	sb.width = width
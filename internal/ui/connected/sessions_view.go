@@ -0,0 +1,237 @@
+package connected
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// Refresh interval bounds of the sessions view
+const (
+	defaultSessionsRefresh = 5 * time.Second
+	minSessionsRefresh     = time.Second
+	maxSessionsRefresh     = time.Minute
+)
+
+// SessionsView lists the server's backends, like pg_stat_activity, and is
+// refreshed every few seconds while shown
+type SessionsView struct {
+	backends   []db.Backend
+	fetched    time.Time // when backends were listed
+	err        error
+	loading    bool
+	cursor     int
+	scroll     int
+	refresh    time.Duration
+	generation int // bumped each time the view is opened, see Open
+	width      int
+	height     int
+}
+
+// NewSessionsView creates an empty sessions view
+func NewSessionsView() *SessionsView {
+	return &SessionsView{
+		refresh: defaultSessionsRefresh,
+	}
+}
+
+// Update handles messages
+func (sv *SessionsView) Update(msg tea.Msg) (*SessionsView, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "up", "k":
+			sv.cursor--
+		case "down", "j":
+			sv.cursor++
+		case "pageup":
+			sv.cursor -= sv.visibleRows()
+		case "pagedown":
+			sv.cursor += sv.visibleRows()
+		case "home":
+			sv.cursor = 0
+		case "end":
+			sv.cursor = len(sv.backends) - 1
+		case "+", "=":
+			if sv.refresh < maxSessionsRefresh {
+				sv.refresh += time.Second
+			}
+		case "-":
+			if sv.refresh > minSessionsRefresh {
+				sv.refresh -= time.Second
+			}
+		}
+		sv.clampCursor()
+	}
+	return sv, nil
+}
+
+// View renders the backend list
+func (sv *SessionsView) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("62")).
+		Bold(true).
+		PaddingLeft(1)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		PaddingLeft(1)
+
+	title := titleStyle.Render(fmt.Sprintf("Sessions (%d)", len(sv.backends))) +
+		dimStyle.Render(fmt.Sprintf("every %v (+/-) · c: cancel query · x: terminate · r: refresh · Esc: back", sv.refresh))
+
+	switch {
+	case sv.loading && sv.backends == nil:
+		return title + "\n\n" + dimStyle.Render("Loading...")
+	case sv.err != nil:
+		return title + "\n\n" + lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			PaddingLeft(1).
+			Width(max(sv.width-2, 20)).
+			Render("Error: "+sv.err.Error())
+	case len(sv.backends) == 0:
+		return title + "\n\n" + dimStyle.Render("No other sessions")
+	}
+
+	headers := []string{"PID", "User", "Application", "State", "Wait", "Started", "Query"}
+	rows := make([][]string, len(sv.backends))
+	for i, b := range sv.backends {
+		rows[i] = []string{
+			strconv.Itoa(b.PID),
+			b.User,
+			b.Application,
+			b.State,
+			b.WaitEvent,
+			sv.queryStarted(b),
+			strings.Join(strings.Fields(b.Query), " "),
+		}
+	}
+
+	// Every column but the query fits its content; the query gets the rest
+	widths := make([]int, len(headers))
+	used := 0
+	for i, header := range headers[:len(headers)-1] {
+		widths[i] = runeWidth(header)
+		for _, row := range rows {
+			widths[i] = min(max(widths[i], runeWidth(row[i])), 24)
+		}
+		used += widths[i] + 2
+	}
+	widths[len(widths)-1] = max(sv.width-2-used, 10)
+
+	joinRow := func(cells []string) string {
+		padded := make([]string, len(cells))
+		for i, cell := range cells {
+			padded[i] = padToWidth(truncateString(cell, widths[i]), widths[i])
+		}
+		return " " + strings.Join(padded, "  ")
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("252"))
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("63")).
+		Background(lipgloss.Color("237")).
+		Bold(true)
+	activeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	idleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	waitingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("208"))
+
+	lines := []string{headerStyle.Render(joinRow(headers))}
+	end := min(sv.scroll+sv.visibleRows(), len(rows))
+	for i := sv.scroll; i < end; i++ {
+		style := idleStyle
+		switch {
+		case i == sv.cursor:
+			style = selectedStyle
+		case sv.backends[i].WaitEvent != "" && sv.backends[i].State == "active":
+			style = waitingStyle
+		case sv.backends[i].State == "active":
+			style = activeStyle
+		}
+		lines = append(lines, style.Render(joinRow(rows[i])))
+	}
+
+	return title + "\n\n" + strings.Join(lines, "\n")
+}
+
+// queryStarted shows when a backend's query started and how long ago
+func (sv *SessionsView) queryStarted(b db.Backend) string {
+	if b.QueryStart.IsZero() {
+		return ""
+	}
+	ago := sv.fetched.Sub(b.QueryStart).Round(time.Second)
+	return b.QueryStart.Local().Format("15:04:05") + " (" + max(ago, 0).String() + ")"
+}
+
+// visibleRows is the number of backends that fit below the header
+func (sv *SessionsView) visibleRows() int {
+	return max(sv.height-5, 1)
+}
+
+// clampCursor keeps the cursor on a backend and in view
+func (sv *SessionsView) clampCursor() {
+	sv.cursor = min(max(sv.cursor, 0), max(len(sv.backends)-1, 0))
+	if sv.cursor < sv.scroll {
+		sv.scroll = sv.cursor
+	}
+	if sv.cursor >= sv.scroll+sv.visibleRows() {
+		sv.scroll = sv.cursor - sv.visibleRows() + 1
+	}
+}
+
+// Open starts a new round of refreshes and returns its generation. Refreshes
+// of an earlier generation are stale and should stop.
+func (sv *SessionsView) Open() int {
+	sv.generation++
+	sv.loading = true
+	return sv.generation
+}
+
+// Generation is the current round of refreshes
+func (sv *SessionsView) Generation() int {
+	return sv.generation
+}
+
+// Interval is how often the list is refreshed
+func (sv *SessionsView) Interval() time.Duration {
+	return sv.refresh
+}
+
+// SetBackends shows a fresh list, keeping the selected backend selected
+func (sv *SessionsView) SetBackends(backends []db.Backend, err error) {
+	sv.loading = false
+	sv.err = err
+	if err != nil {
+		return
+	}
+
+	selected, hadSelection := sv.SelectedBackend()
+	sv.backends = backends
+	sv.fetched = time.Now()
+	if hadSelection {
+		for i, b := range backends {
+			if b.PID == selected.PID {
+				sv.cursor = i
+				break
+			}
+		}
+	}
+	sv.clampCursor()
+}
+
+// SelectedBackend returns the backend under the cursor
+func (sv *SessionsView) SelectedBackend() (db.Backend, bool) {
+	if sv.cursor < 0 || sv.cursor >= len(sv.backends) {
+		return db.Backend{}, false
+	}
+	return sv.backends[sv.cursor], true
+}
+
+// SetDimensions sets width and height
+func (sv *SessionsView) SetDimensions(width, height int) {
+	sv.width = width
+	sv.height = height
+}
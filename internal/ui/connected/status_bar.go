@@ -6,6 +6,16 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/imran-vz/gosqlit/internal/db"
+)
+
+// ConnState is the health of a tab's connection, as the heartbeat sees it
+type ConnState int
+
+const (
+	ConnConnected    ConnState = iota // heartbeats succeed
+	ConnReconnecting                  // heartbeat failed, reconnecting
+	ConnLost                          // reconnecting keeps failing
 )
 
 // StatusBar displays connection and query info
@@ -14,7 +24,13 @@ type StatusBar struct {
 	queryTime    time.Duration
 	rowCount     int
 	errorMsg     string
+	infoMsg      string
+	commandTag   string
+	manualCommit bool
+	txStatus     db.TxStatus
 	queryRunning bool
+	connState    ConnState
+	connDetail   string // e.g. when the next reconnect attempt is due
 	width        int
 }
 
@@ -48,13 +64,17 @@ func (sb *StatusBar) View() string {
 
 	if sb.errorMsg != "" {
 		right = errorStyle.Render("Error: " + sb.errorMsg)
+	} else if sb.infoMsg != "" {
+		right = rightStyle.Render(sb.infoMsg)
 	} else if sb.queryRunning {
 		right = rightStyle.Render("⏳ Running... (Ctrl+K to cancel)")
+	} else if sb.commandTag != "" {
+		right = rightStyle.Render(fmt.Sprintf("✓ %s in %v", sb.commandTag, sb.queryTime))
 	} else if sb.queryTime > 0 {
 		right = rightStyle.Render(fmt.Sprintf("✓ %d rows in %v", sb.rowCount, sb.queryTime))
 	}
 
-	leftRendered := leftStyle.Render(left)
+	leftRendered := sb.renderConnState() + leftStyle.Render(left) + sb.renderTxState()
 	rightRendered := right
 
 	// Calculate spacing
@@ -72,20 +92,95 @@ func (sb *StatusBar) View() string {
 	return leftRendered + spacerStyle.Render(spacer) + rightRendered
 }
 
+// renderConnState renders the connection state badge
+func (sb *StatusBar) renderConnState() string {
+	style := lipgloss.NewStyle().
+		Background(lipgloss.Color("235")).
+		Bold(true).
+		PaddingLeft(1)
+
+	var badge string
+	switch sb.connState {
+	case ConnConnected:
+		return style.Foreground(lipgloss.Color("42")).Render("●")
+	case ConnReconnecting:
+		badge = style.Foreground(lipgloss.Color("214")).Render("↻ RECONNECTING")
+	case ConnLost:
+		badge = style.Foreground(lipgloss.Color("196")).Render("✖ CONNECTION LOST")
+	}
+	if sb.connDetail != "" {
+		badge += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("244")).
+			Background(lipgloss.Color("235")).
+			PaddingLeft(1).
+			Render(sb.connDetail)
+	}
+	return badge
+}
+
+// renderTxState renders the commit mode and transaction badges
+func (sb *StatusBar) renderTxState() string {
+	var out string
+
+	if sb.manualCommit {
+		out += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("252")).
+			Background(lipgloss.Color("238")).
+			Padding(0, 1).
+			Render("MANUAL COMMIT")
+	}
+
+	switch sb.txStatus {
+	case db.TxActive:
+		out += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("0")).
+			Background(lipgloss.Color("214")).
+			Bold(true).
+			Padding(0, 1).
+			Render("IN TRANSACTION")
+	case db.TxFailed:
+		out += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("255")).
+			Background(lipgloss.Color("160")).
+			Bold(true).
+			Padding(0, 1).
+			Render("FAILED TRANSACTION (F8 to roll back)")
+	}
+
+	return out
+}
+
 // SetQueryResult sets successful query result
 func (sb *StatusBar) SetQueryResult(rowCount int, elapsed time.Duration) {
 	sb.rowCount = rowCount
 	sb.queryTime = elapsed
+	sb.commandTag = ""
 	sb.errorMsg = ""
+	sb.infoMsg = ""
+	sb.queryRunning = false
+}
+
+// SetExecResult sets the command tag of a statement that returned no rows
+func (sb *StatusBar) SetExecResult(commandTag string, elapsed time.Duration) {
+	if commandTag == "" {
+		commandTag = "OK"
+	}
+	sb.commandTag = commandTag
+	sb.queryTime = elapsed
+	sb.rowCount = 0
+	sb.errorMsg = ""
+	sb.infoMsg = ""
 	sb.queryRunning = false
 }
 
 // SetError sets error message
 func (sb *StatusBar) SetError(err string) {
 	sb.errorMsg = err
+	sb.infoMsg = ""
 	sb.queryRunning = false
 	sb.queryTime = 0
 	sb.rowCount = 0
+	sb.commandTag = ""
 }
 
 // SetQueryRunning sets running state
@@ -93,9 +188,30 @@ func (sb *StatusBar) SetQueryRunning(running bool) {
 	sb.queryRunning = running
 	if running {
 		sb.errorMsg = ""
+		sb.infoMsg = ""
 	}
 }
 
+// SetInfo sets a neutral message, such as the outcome of an export
+func (sb *StatusBar) SetInfo(msg string) {
+	sb.infoMsg = msg
+	sb.errorMsg = ""
+	sb.queryRunning = false
+}
+
+// SetTxState sets the commit mode and transaction state shown
+func (sb *StatusBar) SetTxState(manualCommit bool, status db.TxStatus) {
+	sb.manualCommit = manualCommit
+	sb.txStatus = status
+}
+
+// SetConnState sets the connection state shown, with a detail such as when
+// the next reconnect attempt is due
+func (sb *StatusBar) SetConnState(state ConnState, detail string) {
+	sb.connState = state
+	sb.connDetail = detail
+}
+
 // SetWidth sets status bar width
 func (sb *StatusBar) SetWidth(width int) {
 	sb.width = width
@@ -16,6 +16,7 @@ type ExplorerView struct {
 	scroll      int
 	width       int
 	height      int
+	err         string // last connection failure, cleared on the next key
 }
 
 // NewExplorer creates explorer view
@@ -36,6 +37,7 @@ func (e *ExplorerView) Update(msg tea.Msg) (*ExplorerView, tea.Cmd) {
 		return e, nil
 
 	case tea.KeyMsg:
+		e.err = ""
 		switch msg.String() {
 		case "up", "k":
 			if e.cursor > 0 {
@@ -121,6 +123,15 @@ func (e *ExplorerView) View() string {
 				conn.Host,
 				conn.Port,
 			)
+			if conn.Port == 0 {
+				// File-based databases have no host
+				line = fmt.Sprintf("%s%-25s  %s @ %s",
+					cursor,
+					conn.Name,
+					conn.Driver,
+					conn.Database,
+				)
+			}
 
 			if i == e.cursor {
 				content += selectedStyle.Render(line) + "\n"
@@ -140,6 +151,14 @@ func (e *ExplorerView) View() string {
 		}
 	}
 
+	if e.err != "" {
+		content += "\n" + lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(0, 2).
+			Width(max(e.width, 20)).
+			Render(e.err)
+	}
+
 	// Help
 	content += "\n\n"
 	content += helpStyle.Render("↑/↓: navigate  Enter: connect  n: new  d: delete  e: edit  q: quit")
@@ -162,6 +181,11 @@ func (e *ExplorerView) GetSelectedConnection() *config.SavedConnection {
 	return nil
 }
 
+// SetError shows a connection failure under the list
+func (e *ExplorerView) SetError(err string) {
+	e.err = err
+}
+
 // SetConnections updates connection list
 func (e *ExplorerView) SetConnections(connections []config.SavedConnection) {
 	e.connections = connections
@@ -0,0 +1,85 @@
+package modal
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ConfirmModal asks a yes/no question before a destructive action
+type ConfirmModal struct {
+	title     string
+	message   string
+	onConfirm tea.Cmd
+	confirmed bool
+	isOpen    bool
+}
+
+// NewConfirm creates a confirmation modal that runs onConfirm on yes
+func NewConfirm(title, message string, onConfirm tea.Cmd) *ConfirmModal {
+	return &ConfirmModal{
+		title:     title,
+		message:   message,
+		onConfirm: onConfirm,
+		isOpen:    true,
+	}
+}
+
+// Init initializes the modal
+func (m *ConfirmModal) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages (tea.Model interface)
+func (m *ConfirmModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "y", "Y", "enter":
+			m.confirmed = true
+			m.isOpen = false
+			return m, m.onConfirm
+		case "n", "N", "esc", "ctrl+c":
+			m.isOpen = false
+		}
+	}
+	return m, nil
+}
+
+// View renders the modal (tea.Model interface)
+func (m *ConfirmModal) View() string {
+	return m.ViewSized(80, 24) // Default size
+}
+
+// ViewSized renders with specific dimensions
+func (m *ConfirmModal) ViewSized(width, height int) string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("214"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("214")).
+		Padding(1, 2).
+		Width(50)
+
+	content := titleStyle.Render(m.title) + "\n\n"
+	content += m.message + "\n\n"
+	content += lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("y/Enter: confirm • n/Esc: cancel")
+
+	return lipgloss.Place(
+		width,
+		height,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(content),
+	)
+}
+
+// IsOpen returns true if modal is open
+func (m *ConfirmModal) IsOpen() bool {
+	return m.isOpen
+}
+
+// IsConfirmed returns true if the user said yes
+func (m *ConfirmModal) IsConfirmed() bool {
+	return m.confirmed
+}
@@ -1,64 +1,216 @@
 package modal
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/google/uuid"
 	"github.com/imran-vz/gosqlit/internal/config"
+	"github.com/imran-vz/gosqlit/internal/db"
 )
 
-// ConnectionFormModal is form for adding/editing connections
+// ConnectionFormModal is form for adding/editing connections. Fields after
+// the name and driver come from the selected driver's description.
 type ConnectionFormModal struct {
 	fields    []formField
+	values    map[string]string // typed values by field key, kept across driver changes
 	focusIdx  int
 	isOpen    bool
 	submitted bool
 	isEdit    bool
-	connID    string
+	conn      config.SavedConnection // connection being edited
+	err       string
+
+	// The connection URL the form was filled from, kept as the saved DSN
+	// unless a field it sets is edited, along with those fields' values
+	dsn       string
+	dsnValues map[string]string
 }
 
 type formField struct {
-	label   string
-	value   string
-	masked  bool
-	options []string // For driver selection
+	key      string // db.Field key, "" for the name and driver
+	label    string
+	value    string
+	def      string // driver default
+	masked   bool
+	numeric  bool
+	path     bool
+	optional bool
+	options  []string // For driver selection
 }
 
+// formChromeHeight is the height of the form around its fields: title,
+// scroll hints, error, help and the box's border and padding
+const formChromeHeight = 18
+
+// Positions of the fields every driver has
+const (
+	nameFieldIdx   = 0
+	driverFieldIdx = 1
+)
+
 // NewConnectionForm creates connection form modal
 func NewConnectionForm(existingConn *config.SavedConnection) *ConnectionFormModal {
-	fields := []formField{
-		{label: "Connection Name", value: "", masked: false},
-		{label: "Driver", value: "postgres", masked: false, options: []string{"postgres"}},
-		{label: "Host", value: "localhost", masked: false},
-		{label: "Port", value: "5432", masked: false},
-		{label: "Username", value: "", masked: false},
-		{label: "Password", value: "", masked: true},
-		{label: "Database", value: "", masked: false},
+	cf := &ConnectionFormModal{
+		values: make(map[string]string),
+		isOpen: true,
+		conn:   config.SavedConnection{ID: uuid.New().String(), Driver: "postgres"},
 	}
 
-	isEdit := false
-	connID := uuid.New().String()
-
 	if existingConn != nil {
-		isEdit = true
-		connID = existingConn.ID
-		fields[0].value = existingConn.Name
-		fields[1].value = existingConn.Driver
-		fields[2].value = existingConn.Host
-		fields[3].value = fmt.Sprintf("%d", existingConn.Port)
-		fields[4].value = existingConn.Username
-		fields[5].value = existingConn.Password
-		fields[6].value = existingConn.Database
+		cf.isEdit = true
+		cf.conn = *existingConn
+		cf.setValues(db.ConnConfig{
+			Host:     existingConn.Host,
+			Port:     existingConn.Port,
+			Username: existingConn.Username,
+			Password: existingConn.Password,
+			Database: existingConn.Database,
+			Params:   existingConn.Params,
+			TLS:      db.TLSConfig(existingConn.TLS),
+		})
+		cf.setSettingValues(*existingConn)
+
+		// A connection saved as a URL is edited as its parts
+		if existingConn.DSN != "" {
+			if driverName, config, err := db.ParseURL(existingConn.DSN); err == nil {
+				cf.conn.Driver = driverName
+				cf.setValues(config)
+				cf.dsn = existingConn.DSN
+			}
+		}
+	}
+
+	cf.fields = []formField{
+		{label: "Connection Name", value: cf.conn.Name},
+		{label: "Driver", value: cf.conn.Driver, options: db.ListDrivers()},
+	}
+	cf.buildDriverFields()
+
+	if cf.dsn != "" {
+		cf.dsnValues = cf.urlValues()
+	}
+
+	return cf
+}
+
+// setValues fills the driver field values from config
+func (cf *ConnectionFormModal) setValues(config db.ConnConfig) {
+	cf.values[db.FieldHost] = config.Host
+	cf.values[db.FieldPort] = portString(config.Port)
+	cf.values[db.FieldUsername] = config.Username
+	cf.values[db.FieldPassword] = config.Password
+	cf.values[db.FieldDatabase] = config.Database
+	cf.values[db.FieldParams] = db.EncodeParams(config.Params)
+	cf.values[db.FieldTLSMode] = config.TLS.Mode
+	cf.values[db.FieldTLSCAFile] = config.TLS.CAFile
+	cf.values[db.FieldTLSCertFile] = config.TLS.CertFile
+	cf.values[db.FieldTLSKeyFile] = config.TLS.KeyFile
+	cf.values[db.FieldTLSServerName] = config.TLS.ServerName
+}
+
+// setSettingValues fills the values of fields a connection URL doesn't
+// carry: the SSH tunnel, timeouts and pool size
+func (cf *ConnectionFormModal) setSettingValues(conn config.SavedConnection) {
+	cf.values[db.FieldSSHHost] = conn.SSH.Host
+	cf.values[db.FieldSSHUser] = conn.SSH.User
+	cf.values[db.FieldSSHKeyFile] = conn.SSH.KeyFile
+	cf.values[db.FieldSSHKnownHosts] = conn.SSH.KnownHostsFile
+	cf.values[db.FieldQueryTimeout] = numberString(conn.Timeout)
+	cf.values[db.FieldConnectTimeout] = numberString(conn.ConnectTimeout)
+	cf.values[db.FieldStatementTimeout] = numberString(conn.StatementTimeout)
+	cf.values[db.FieldLockTimeout] = numberString(conn.LockTimeout)
+	cf.values[db.FieldMaxConns] = numberString(conn.MaxConns)
+	cf.values[db.FieldMinConns] = numberString(conn.MinConns)
+}
+
+// applyURL replaces the driver and its field values with those of a pasted
+// connection URL, reporting false if raw isn't one. Settings the URL doesn't
+// carry keep their values.
+func (cf *ConnectionFormModal) applyURL(raw string) bool {
+	driverName, config, err := db.ParseURL(raw)
+	if errors.Is(err, db.ErrMultipleHosts) {
+		cf.err = err.Error()
+		return true
+	}
+	if err != nil {
+		return false
+	}
+	cf.err = ""
+
+	cf.saveFieldValues()
+	cf.setValues(config)
+	cf.fields[driverFieldIdx].value = driverName
+	cf.fields = cf.fields[:driverFieldIdx+1]
+	cf.buildDriverFields()
+
+	cf.dsn = strings.TrimSpace(raw)
+	cf.dsnValues = cf.urlValues()
+
+	if cf.focusIdx >= len(cf.fields) {
+		cf.focusIdx = len(cf.fields) - 1
+	}
+	return true
+}
+
+// urlValues returns the driver and the values of the fields a connection
+// URL sets
+func (cf *ConnectionFormModal) urlValues() map[string]string {
+	values := map[string]string{"": cf.fields[driverFieldIdx].value}
+	for _, field := range cf.fields[driverFieldIdx+1:] {
+		switch field.key {
+		case db.FieldHost, db.FieldPort, db.FieldUsername, db.FieldPassword, db.FieldDatabase, db.FieldParams,
+			db.FieldTLSMode, db.FieldTLSCAFile, db.FieldTLSCertFile, db.FieldTLSKeyFile, db.FieldTLSServerName:
+			values[field.key] = field.value
+		}
+	}
+	return values
+}
+
+// saveFieldValues records the driver field values typed so far, dropping
+// those left at the driver's default
+func (cf *ConnectionFormModal) saveFieldValues() {
+	for _, field := range cf.fields[driverFieldIdx+1:] {
+		if field.value == field.def {
+			delete(cf.values, field.key)
+		} else {
+			cf.values[field.key] = field.value
+		}
+	}
+}
+
+// buildDriverFields lays out the selected driver's fields, keeping values
+// typed for fields the previous driver shared unless they were its defaults
+func (cf *ConnectionFormModal) buildDriverFields() {
+	cf.saveFieldValues()
+
+	cf.fields = cf.fields[:driverFieldIdx+1]
+	driver, err := db.GetDriver(cf.fields[driverFieldIdx].value)
+	if err != nil {
+		return
 	}
 
-	return &ConnectionFormModal{
-		fields:   fields,
-		focusIdx: 0,
-		isOpen:   true,
-		isEdit:   isEdit,
-		connID:   connID,
+	for _, f := range driver.Fields() {
+		value, ok := cf.values[f.Key]
+		if !ok || value == "" {
+			value = f.Default
+		}
+		cf.fields = append(cf.fields, formField{
+			key:      f.Key,
+			label:    f.Label,
+			value:    value,
+			def:      f.Default,
+			masked:   f.Secret,
+			numeric:  f.Type == db.FieldNumber,
+			path:     f.Type == db.FieldPath,
+			optional: f.Optional,
+			options:  f.Options,
+		})
 	}
 }
 
@@ -82,15 +234,21 @@ func (cf *ConnectionFormModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cf.focusIdx = len(cf.fields) - 1
 			}
 		case "left":
-			if len(cf.fields[cf.focusIdx].value) > 0 {
+			if len(cf.fields[cf.focusIdx].options) > 0 {
+				cf.cycleOption(-1)
+			} else if len(cf.fields[cf.focusIdx].value) > 0 {
 				cf.fields[cf.focusIdx].value = cf.fields[cf.focusIdx].value[:len(cf.fields[cf.focusIdx].value)-1]
 			}
 		case "right":
-			if len(cf.fields[cf.focusIdx].value) < len(cf.fields[cf.focusIdx].options) {
-				cf.fields[cf.focusIdx].value = cf.fields[cf.focusIdx].options[len(cf.fields[cf.focusIdx].value)]
+			if len(cf.fields[cf.focusIdx].options) > 0 {
+				cf.cycleOption(1)
 			}
 		case "enter":
 			// Save
+			if err := cf.validate(); err != "" {
+				cf.err = err
+				return cf, nil
+			}
 			cf.submitted = true
 			cf.isOpen = false
 			return cf, nil
@@ -105,15 +263,29 @@ func (cf *ConnectionFormModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Select all (just clear for now, easier to retype)
 			cf.fields[cf.focusIdx].value = ""
 		default:
+			// Option fields are changed with left/right only
+			if len(cf.fields[cf.focusIdx].options) > 0 {
+				return cf, nil
+			}
+
 			// Type characters (including paste support)
 			input := keyMsg.String()
 
 			// Strip bracketed paste mode markers
 			input = stripPasteMarkers(input)
 
+			// A pasted connection URL fills in every field
+			if strings.Contains(input, "://") && cf.applyURL(input) {
+				return cf, nil
+			}
+
 			// Filter out control characters but allow printable chars
 			if len(input) > 0 && !isControlKey(input) {
+				if cf.fields[cf.focusIdx].numeric && !isDigits(input) {
+					return cf, nil
+				}
 				cf.fields[cf.focusIdx].value += input
+				cf.err = ""
 			}
 		}
 	}
@@ -121,6 +293,54 @@ func (cf *ConnectionFormModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return cf, nil
 }
 
+// cycleOption selects the next or previous option of the focused field
+func (cf *ConnectionFormModal) cycleOption(step int) {
+	field := &cf.fields[cf.focusIdx]
+
+	idx := 0
+	for i, opt := range field.options {
+		if opt == field.value {
+			idx = i
+			break
+		}
+	}
+	idx = (idx + step + len(field.options)) % len(field.options)
+
+	previous := field.value
+	field.value = field.options[idx]
+
+	if cf.focusIdx == driverFieldIdx && previous != field.value {
+		cf.buildDriverFields()
+		cf.err = ""
+	}
+}
+
+// validate returns a message naming the first required field left empty or
+// the first setting that can't be used
+func (cf *ConnectionFormModal) validate() string {
+	if cf.fields[nameFieldIdx].value == "" {
+		return "Connection Name is required"
+	}
+
+	conn := cf.GetConnection()
+	pool := db.ConnConfig{MaxConns: conn.MaxConns, MinConns: conn.MinConns}
+	if _, _, err := pool.PoolSize(); err != nil {
+		return err.Error()
+	}
+
+	for _, field := range cf.fields[driverFieldIdx+1:] {
+		if field.value == "" && !field.optional {
+			return field.label + " is required"
+		}
+		if field.key == db.FieldParams {
+			if _, err := db.ParseParams(field.value); err != nil {
+				return err.Error()
+			}
+		}
+	}
+	return ""
+}
+
 // View renders modal
 func (cf *ConnectionFormModal) View() string {
 	return cf.ViewSized(80, 24)
@@ -160,19 +380,37 @@ func (cf *ConnectionFormModal) ViewSized(width, height int) string {
 
 	content := titleStyle.Render(title) + "\n\n"
 
-	for i, field := range cf.fields {
+	// Scroll the fields to keep the focused one in view when they don't fit
+	start, end := 0, len(cf.fields)
+	if visible := max(height-formChromeHeight, 5); len(cf.fields) > visible {
+		start = min(max(cf.focusIdx-visible/2, 0), len(cf.fields)-visible)
+		end = start + visible
+	}
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	if start > 0 {
+		content += hintStyle.Render(fmt.Sprintf("  ↑ %d more", start)) + "\n"
+	}
+
+	for i := start; i < end; i++ {
+		field := cf.fields[i]
 		label := labelStyle.Render(field.label + ":")
 
 		value := field.value
 		if field.masked && value != "" {
 			value = maskString(value)
 		}
+		input := inputStyle.Render(value)
 		if value == "" {
-			value = "____________"
+			placeholder := "____________"
+			switch {
+			case field.optional:
+				placeholder = "(optional)"
+			case field.path:
+				placeholder = "path/to/file.db"
+			}
+			input = inputStyle.Foreground(lipgloss.Color("240")).Render(placeholder)
 		}
 
-		input := inputStyle.Render(value)
-
 		if i == cf.focusIdx {
 			label = focusedStyle.Render("> " + field.label + ":")
 			input = focusedStyle.Render(input)
@@ -182,11 +420,19 @@ func (cf *ConnectionFormModal) ViewSized(width, height int) string {
 
 		content += label + " " + input + "\n"
 	}
+	if end < len(cf.fields) {
+		content += hintStyle.Render(fmt.Sprintf("  ↓ %d more", len(cf.fields)-end)) + "\n"
+	}
 
 	content += "\n"
+	if cf.err != "" {
+		content += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Render(cf.err) + "\n\n"
+	}
 	content += lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240")).
-		Render("Tab/↑↓: navigate  Ctrl+U: clear field  Enter: save  Esc: cancel")
+		Render("Tab/↑↓: navigate  ←→: change option  Ctrl+U: clear field  Enter: save  Esc: cancel\nPaste a connection URL into any field to fill in the form")
 
 	box := boxStyle.Render(content)
 
@@ -204,32 +450,87 @@ func (cf *ConnectionFormModal) IsOpen() bool {
 	return cf.isOpen
 }
 
-// GetConnection returns connection from form
+// GetConnection returns connection from form. Settings the selected driver
+// doesn't take are cleared; others not on the form are kept when editing.
 func (cf *ConnectionFormModal) GetConnection() config.SavedConnection {
-	port := 5432
-	if cf.fields[3].value != "" {
-		// Simple int parse
-		p := 0
-		for _, c := range cf.fields[3].value {
-			if c >= '0' && c <= '9' {
-				p = p*10 + int(c-'0')
-			}
-		}
-		if p > 0 {
-			port = p
-		}
+	conn := cf.conn
+	conn.Name = cf.fields[nameFieldIdx].value
+	conn.Driver = cf.fields[driverFieldIdx].value
+	conn.Host = ""
+	conn.Port = 0
+	conn.Username = ""
+	conn.Password = ""
+	conn.Database = ""
+	conn.Params = nil
+	conn.DSN = ""
+	conn.TLS = config.TLSSettings{}
+	conn.SSH = config.SSHSettings{}
+	conn.Timeout = 0
+	conn.ConnectTimeout = 0
+	conn.StatementTimeout = 0
+	conn.LockTimeout = 0
+	conn.MaxConns = 0
+	conn.MinConns = 0
+
+	// The URL takes the place of the fields it set until one is edited;
+	// it may hold what they can't, like several hosts
+	if cf.dsn != "" && maps.Equal(cf.urlValues(), cf.dsnValues) {
+		conn.DSN = cf.dsn
 	}
 
-	return config.SavedConnection{
-		ID:       cf.connID,
-		Name:     cf.fields[0].value,
-		Driver:   cf.fields[1].value,
-		Host:     cf.fields[2].value,
-		Port:     port,
-		Username: cf.fields[4].value,
-		Password: cf.fields[5].value,
-		Database: cf.fields[6].value,
+	for _, field := range cf.fields[driverFieldIdx+1:] {
+		switch field.key {
+		case db.FieldHost:
+			conn.Host = field.value
+		case db.FieldPort:
+			conn.Port, _ = strconv.Atoi(field.value)
+		case db.FieldUsername:
+			conn.Username = field.value
+		case db.FieldPassword:
+			conn.Password = field.value
+		case db.FieldDatabase:
+			conn.Database = field.value
+		case db.FieldParams:
+			conn.Params, _ = db.ParseParams(field.value)
+		case db.FieldTLSMode:
+			conn.TLS.Mode = field.value
+		case db.FieldTLSCAFile:
+			conn.TLS.CAFile = field.value
+		case db.FieldTLSCertFile:
+			conn.TLS.CertFile = field.value
+		case db.FieldTLSKeyFile:
+			conn.TLS.KeyFile = field.value
+		case db.FieldTLSServerName:
+			conn.TLS.ServerName = field.value
+		case db.FieldSSHHost:
+			conn.SSH.Host = field.value
+		case db.FieldSSHUser:
+			conn.SSH.User = field.value
+		case db.FieldSSHKeyFile:
+			conn.SSH.KeyFile = field.value
+		case db.FieldSSHKnownHosts:
+			conn.SSH.KnownHostsFile = field.value
+		case db.FieldQueryTimeout:
+			conn.Timeout, _ = strconv.Atoi(field.value)
+		case db.FieldConnectTimeout:
+			conn.ConnectTimeout, _ = strconv.Atoi(field.value)
+		case db.FieldStatementTimeout:
+			conn.StatementTimeout, _ = strconv.Atoi(field.value)
+		case db.FieldLockTimeout:
+			conn.LockTimeout, _ = strconv.Atoi(field.value)
+		case db.FieldMaxConns:
+			conn.MaxConns, _ = strconv.Atoi(field.value)
+		case db.FieldMinConns:
+			conn.MinConns, _ = strconv.Atoi(field.value)
+		}
 	}
+
+	return conn
+}
+
+// IsEdit returns true if the form edits a saved connection
+func (cf *ConnectionFormModal) IsEdit() bool {
+	return cf.isEdit
 }
 
 // IsSubmitted returns true if submitted
@@ -237,6 +538,32 @@ func (cf *ConnectionFormModal) IsSubmitted() bool {
 	return cf.submitted
 }
 
+// portString formats a port for display, leaving it empty for file-based drivers
+func portString(port int) string {
+	if port == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", port)
+}
+
+// numberString formats a setting for display, leaving it empty when unset
+func numberString(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// isDigits reports whether s is made of ASCII digits only
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 func maskString(s string) string {
 	result := ""
 	for range s {
@@ -4,20 +4,28 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/imran-vz/gosqlit/internal/app"
 	"github.com/imran-vz/gosqlit/internal/config"
 	"github.com/imran-vz/gosqlit/internal/debug"
+	"github.com/imran-vz/gosqlit/internal/format"
 	"github.com/imran-vz/gosqlit/internal/ui/modal"
 
 	// Import drivers to register them
+	_ "github.com/imran-vz/gosqlit/internal/db/drivers/mysql"
 	_ "github.com/imran-vz/gosqlit/internal/db/drivers/postgres"
+	_ "github.com/imran-vz/gosqlit/internal/db/drivers/sqlite"
 )
 
 var (
 	debugMode = flag.Bool("debug", false, "Enable debug mode")
 	logFile   = flag.String("log", "", "Debug log file (default: stderr)")
+
+	timeZone   = flag.String("tz", "", "Time zone to show timestamps in, e.g. UTC or Europe/Berlin (default: as returned)")
+	timeLayout = flag.String("time-format", "", "Go time layout for timestamps (default: 2006-01-02 15:04:05.999999)")
+	byteaMode  = flag.String("bytea", "hex", "How to show binary values: hex or escape")
 )
 
 func main() {
@@ -32,6 +40,11 @@ func main() {
 
 	debug.Logf("Starting gosqlit with debug mode: %v", *debugMode)
 
+	if err := setFormatOptions(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Check if config exists
 	tmpMgr, err := config.NewManager("")
 	if err != nil {
@@ -106,3 +119,29 @@ func main() {
 
 	debug.Log("Application ended normally")
 }
+
+// setFormatOptions applies the value display flags
+func setFormatOptions() error {
+	opts := format.Defaults()
+	opts.TimeLayout = *timeLayout
+
+	if *timeZone != "" {
+		loc, err := time.LoadLocation(*timeZone)
+		if err != nil {
+			return fmt.Errorf("invalid time zone: %w", err)
+		}
+		opts.Location = loc
+	}
+
+	switch *byteaMode {
+	case "hex":
+		opts.Binary = format.BinaryHex
+	case "escape":
+		opts.Binary = format.BinaryEscape
+	default:
+		return fmt.Errorf("invalid -bytea value %q: must be hex or escape", *byteaMode)
+	}
+
+	format.SetDefaults(opts)
+	return nil
+}
@@ -14,6 +14,21 @@ type Node struct {
 	Children []*Node
 	Expanded bool
 	Data     interface{} // Custom data
+	Lazy     bool        // Children are loaded on first expand, see ExpandMsg
+	Loading  bool        // Children are being loaded
+
+	Color lipgloss.TerminalColor // label colour when not selected, nil for the default
+}
+
+// ExpandMsg is sent when a lazy node is expanded. The receiver loads its
+// children and hands them to SetChildren, or calls LoadFailed.
+type ExpandMsg struct {
+	Node *Node
+}
+
+// expandable reports whether node has or can load children
+func (n *Node) expandable() bool {
+	return n.Lazy || len(n.Children) > 0
 }
 
 // Tree is a reusable tree component
@@ -48,6 +63,7 @@ func (t *Tree) rebuild() {
 	}
 
 	// Update selected node
+	t.selected = nil
 	if t.cursor >= 0 && t.cursor < len(t.flatList) {
 		t.selected = t.flatList[t.cursor]
 	}
@@ -84,9 +100,8 @@ func (t *Tree) Update(msg tea.Msg) (*Tree, tea.Cmd) {
 			}
 		case "right", "l":
 			// Expand node
-			if t.selected != nil && len(t.selected.Children) > 0 {
-				t.selected.Expanded = true
-				t.rebuild()
+			if t.selected != nil {
+				return t, t.expand(t.selected)
 			}
 		case "left", "h":
 			// Collapse node
@@ -96,9 +111,11 @@ func (t *Tree) Update(msg tea.Msg) (*Tree, tea.Cmd) {
 			}
 		case "enter", " ":
 			// Toggle expand
-			if t.selected != nil && len(t.selected.Children) > 0 {
-				t.selected.Expanded = !t.selected.Expanded
+			if t.selected != nil && t.selected.Expanded {
+				t.selected.Expanded = false
 				t.rebuild()
+			} else if t.selected != nil {
+				return t, t.expand(t.selected)
 			}
 		}
 	}
@@ -106,6 +123,59 @@ func (t *Tree) Update(msg tea.Msg) (*Tree, tea.Cmd) {
 	return t, nil
 }
 
+// expand opens node, asking for its children with an ExpandMsg if it's
+// lazy and they aren't being loaded yet
+func (t *Tree) expand(node *Node) tea.Cmd {
+	if !node.expandable() {
+		return nil
+	}
+	node.Expanded = true
+	t.rebuild()
+
+	if !node.Lazy || node.Loading {
+		return nil
+	}
+	node.Loading = true
+	return func() tea.Msg {
+		return ExpandMsg{Node: node}
+	}
+}
+
+// SetChildren fills in the children of a lazy node, which keeps them until
+// it's replaced
+func (t *Tree) SetChildren(node *Node, children []*Node) {
+	node.Children = children
+	node.Lazy = false
+	node.Loading = false
+	t.rebuild()
+}
+
+// LoadFailed collapses a lazy node whose children couldn't be loaded, so
+// expanding it again retries
+func (t *Tree) LoadFailed(node *Node) {
+	node.Loading = false
+	node.Expanded = false
+	t.rebuild()
+}
+
+// FindNode returns the node with id, or nil
+func (t *Tree) FindNode(id string) *Node {
+	return findNode(t.Root, id)
+}
+
+// findNode searches node and its descendants for id
+func findNode(node *Node, id string) *Node {
+	if node == nil || node.ID == id {
+		return node
+	}
+	for _, child := range node.Children {
+		if found := findNode(child, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
 // View renders the tree
 func (t *Tree) View(width, height int) string {
 	if len(t.flatList) == 0 {
@@ -139,7 +209,7 @@ func (t *Tree) View(width, height int) string {
 
 		// Expand indicator
 		indicator := "  "
-		if len(node.Children) > 0 {
+		if node.expandable() {
 			if node.Expanded {
 				indicator = "▼ "
 			} else {
@@ -148,19 +218,26 @@ func (t *Tree) View(width, height int) string {
 		}
 
 		line := indent + indicator + node.Label
+		if node.Loading {
+			line += " (loading…)"
+		}
 
 		// Highlight selected
+		var color lipgloss.TerminalColor = lipgloss.Color("252")
+		if node.Color != nil {
+			color = node.Color
+		}
+		style := lipgloss.NewStyle().Foreground(color)
 		if i == t.cursor {
-			line = lipgloss.NewStyle().
+			style = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("63")).
 				Background(lipgloss.Color("237")).
-				Bold(true).
-				Render(line)
-		} else {
-			line = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("252")).
-				Render(line)
+				Bold(true)
+		}
+		if width > 0 {
+			style = style.MaxWidth(width)
 		}
+		line = style.Render(line)
 
 		lines = append(lines, line)
 	}